@@ -7,14 +7,9 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 )
 
-func init() {
-	chi.RegisterMethod("LINK")
-	chi.RegisterMethod("UNLINK")
-	chi.RegisterMethod("WOOHOO")
-}
-
 func main() {
 	r := chi.NewRouter()
+	r.RegisterMethod("LINK", "UNLINK", "WOOHOO")
 	r.Use(middleware.RequestID)
 	r.Use(middleware.Logger)
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
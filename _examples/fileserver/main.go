@@ -7,6 +7,8 @@
 // Client requests:
 //
 //	$ curl http://localhost:3333/files/
+//	<!doctype html>
+//	<meta name="viewport" content="width=device-width">
 //	<pre>
 //	<a href="notes.txt">notes.txt</a>
 //	</pre>
@@ -19,7 +21,6 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -35,31 +36,12 @@ func main() {
 	})
 
 	// Create a route along /files that will serve contents from
-	// the ./data/ folder.
+	// the ./data/ folder. Directory listings are disabled by default.
 	workDir, _ := os.Getwd()
-	filesDir := http.Dir(filepath.Join(workDir, "data"))
-	FileServer(r, "/files", filesDir)
+	filesDir := os.DirFS(filepath.Join(workDir, "data"))
+	chi.FileServer(r, "/files", filesDir, &chi.FileServerOptions{
+		DirectoryListing: true,
+	})
 
 	http.ListenAndServe(":3333", r)
 }
-
-// FileServer conveniently sets up a http.FileServer handler to serve
-// static files from a http.FileSystem.
-func FileServer(r chi.Router, path string, root http.FileSystem) {
-	if strings.ContainsAny(path, "{}*") {
-		panic("FileServer does not permit any URL parameters.")
-	}
-
-	if path != "/" && path[len(path)-1] != '/' {
-		r.Get(path, http.RedirectHandler(path+"/", 301).ServeHTTP)
-		path += "/"
-	}
-	path += "*"
-
-	r.Get(path, func(w http.ResponseWriter, r *http.Request) {
-		rctx := chi.RouteContext(r.Context())
-		pathPrefix := strings.TrimSuffix(rctx.RoutePattern(), "/*")
-		fs := http.StripPrefix(pathPrefix, http.FileServer(root))
-		fs.ServeHTTP(w, r)
-	})
-}
@@ -34,6 +34,14 @@ func main() {
 	if err := chi.Walk(r, walkFunc); err != nil {
 		fmt.Printf("Logging err: %s\n", err.Error())
 	}
+
+	// Print the full route table, with params, middlewares and handlers.
+	table, err := chi.ExportRoutes(r, chi.ExportMarkdown)
+	if err != nil {
+		fmt.Printf("Logging err: %s\n", err.Error())
+		return
+	}
+	fmt.Printf("\n%s", table)
 }
 
 // Ping returns pong
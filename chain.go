@@ -1,6 +1,10 @@
 package chi
 
-import "net/http"
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+)
 
 // Chain returns a Middlewares type from a slice of middleware handlers.
 func Chain(middlewares ...func(http.Handler) http.Handler) Middlewares {
@@ -47,3 +51,67 @@ func chain(middlewares []func(http.Handler) http.Handler, endpoint http.Handler)
 
 	return h
 }
+
+// Named returns the middleware `mw` labelled with `name`, so it can be
+// identified in a middleware stack with MiddlewareName, for example when
+// auditing the routes reported by Walk:
+//
+//	r.Use(chi.Named("auth", AuthMiddleware))
+//	r.With(chi.Named("ratelimit", httprate.LimitByIP(100, time.Minute))).Get("/", h)
+//
+// The returned middleware behaves exactly like `mw`.
+//
+//go:noinline
+func Named(name string, mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	if mw == nil {
+		panic("chi: attempting to name a nil middleware")
+	}
+	return func(next http.Handler) http.Handler {
+		if probe, ok := next.(*nameProbe); ok {
+			probe.name = name
+			return next
+		}
+		return mw(next)
+	}
+}
+
+// MiddlewareName returns the name given to the middleware `mw` by Named, or
+// otherwise the name of its function, as reported by the runtime.
+func MiddlewareName(mw func(http.Handler) http.Handler) string {
+	if mw == nil {
+		return ""
+	}
+	pc := reflect.ValueOf(mw).Pointer()
+	if pc == namedPC {
+		probe := &nameProbe{}
+		mw(probe)
+		return probe.name
+	}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
+
+// Names returns the name of every middleware in the stack, in order.
+// See MiddlewareName.
+func (mws Middlewares) Names() []string {
+	names := make([]string, len(mws))
+	for i, mw := range mws {
+		names[i] = MiddlewareName(mw)
+	}
+	return names
+}
+
+// namedPC is the code pointer shared by all middlewares returned by Named.
+// Named is never inlined, as each inlined copy of its closure would have a
+// code pointer of its own.
+var namedPC = reflect.ValueOf(Named("", func(next http.Handler) http.Handler { return next })).Pointer()
+
+// nameProbe is a http.Handler passed to a named middleware to read its name,
+// without building the wrapped middleware.
+type nameProbe struct {
+	name string
+}
+
+func (p *nameProbe) ServeHTTP(http.ResponseWriter, *http.Request) {}
@@ -0,0 +1,293 @@
+// Package chitest provides helpers to test chi routers and handlers.
+//
+// Requests are served by the router with a routing context owned by the
+// test, so the matched route patterns and URL params can be asserted on
+// after the request, for example:
+//
+//	chitest.Do(t, r, "GET", "/users/1").
+//		ExpectStatus(200).
+//		ExpectPattern("/users/{id}").
+//		ExpectParam("id", "1")
+package chitest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Request is a builder of test requests, see NewRequest.
+type Request struct {
+	method string
+	target string
+	header http.Header
+	body   []byte
+	ctx    context.Context
+}
+
+// NewRequest returns a builder for a request with the method and target,
+// which is a path or an absolute URL, as with httptest.NewRequest.
+func NewRequest(method, target string) *Request {
+	return &Request{method: method, target: target, header: http.Header{}}
+}
+
+// Header adds the header to the request.
+func (rb *Request) Header(key, value string) *Request {
+	rb.header.Add(key, value)
+	return rb
+}
+
+// Body sets the body of the request.
+func (rb *Request) Body(body []byte) *Request {
+	rb.body = body
+	return rb
+}
+
+// JSON sets the body of the request to the JSON encoding of v, and its
+// Content-Type header to application/json. It panics if v can't be encoded.
+func (rb *Request) JSON(v any) *Request {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic("chitest: " + err.Error())
+	}
+	rb.header.Set("Content-Type", "application/json")
+	return rb.Body(b)
+}
+
+// Context sets the parent context of the request.
+func (rb *Request) Context(ctx context.Context) *Request {
+	rb.ctx = ctx
+	return rb
+}
+
+// Do serves the request with `h`, and returns the response for assertions
+// reported to `t`.
+func (rb *Request) Do(t testing.TB, h http.Handler) *Response {
+	t.Helper()
+
+	var body io.Reader
+	if rb.body != nil {
+		body = bytes.NewReader(rb.body)
+	}
+	r := httptest.NewRequest(rb.method, rb.target, body)
+	for key, values := range rb.header {
+		r.Header[key] = slices.Clone(values)
+	}
+
+	ctx := rb.ctx
+	if ctx == nil {
+		ctx = r.Context()
+	}
+
+	// Serve the request with a routing context of our own, so it isn't
+	// reset and put back in the router's pool once the request is done.
+	rctx := chi.NewRouteContext()
+	if routes, ok := h.(chi.Routes); ok {
+		rctx.Routes = routes
+	}
+	r = r.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return &Response{
+		Recorder:     w,
+		Request:      r,
+		RouteContext: rctx,
+		t:            t,
+		handler:      h,
+	}
+}
+
+// Do serves a request with the method and target with `h`, and returns the
+// response for assertions reported to `t`. See NewRequest to build more
+// elaborate requests.
+func Do(t testing.TB, h http.Handler, method, target string) *Response {
+	t.Helper()
+	return NewRequest(method, target).Do(t, h)
+}
+
+// Response is the response to a test request, with assertion methods which
+// report failures with t.Errorf and return the response for chaining.
+type Response struct {
+	// Recorder holds the response written by the handler.
+	Recorder *httptest.ResponseRecorder
+
+	// Request is the request as served, with the routing context.
+	Request *http.Request
+
+	// RouteContext is the routing context of the request, as it was once
+	// the request was served.
+	RouteContext *chi.Context
+
+	t       testing.TB
+	handler http.Handler
+}
+
+// ExpectStatus checks the status code of the response.
+func (res *Response) ExpectStatus(code int) *Response {
+	res.t.Helper()
+	if res.Recorder.Code != code {
+		res.t.Errorf("chitest: %s: expected status %d, got %d", res, code, res.Recorder.Code)
+	}
+	return res
+}
+
+// ExpectHeader checks the value of a response header.
+func (res *Response) ExpectHeader(key, value string) *Response {
+	res.t.Helper()
+	if got := res.Recorder.Header().Get(key); got != value {
+		res.t.Errorf("chitest: %s: expected header %s %q, got %q", res, key, value, got)
+	}
+	return res
+}
+
+// ExpectBody checks the body of the response.
+func (res *Response) ExpectBody(body string) *Response {
+	res.t.Helper()
+	if got := res.Recorder.Body.String(); got != body {
+		res.t.Errorf("chitest: %s: expected body %q, got %q", res, body, got)
+	}
+	return res
+}
+
+// ExpectBodyContains checks that the body of the response contains s.
+func (res *Response) ExpectBodyContains(s string) *Response {
+	res.t.Helper()
+	if got := res.Recorder.Body.String(); !strings.Contains(got, s) {
+		res.t.Errorf("chitest: %s: expected body containing %q, got %q", res, s, got)
+	}
+	return res
+}
+
+// ExpectJSON checks that the body of the response is the JSON encoding of
+// a value equal to v, regardless of formatting and the order of object keys.
+func (res *Response) ExpectJSON(v any) *Response {
+	res.t.Helper()
+
+	want, err := json.Marshal(v)
+	if err != nil {
+		res.t.Errorf("chitest: %s: invalid expected JSON value: %v", res, err)
+		return res
+	}
+
+	var got, expected any
+	if err := json.Unmarshal(res.Recorder.Body.Bytes(), &got); err != nil {
+		res.t.Errorf("chitest: %s: expected a JSON body, got %q: %v", res, res.Recorder.Body.String(), err)
+		return res
+	}
+	json.Unmarshal(want, &expected)
+	if !reflect.DeepEqual(got, expected) {
+		res.t.Errorf("chitest: %s: expected JSON body %s, got %s", res, want, bytes.TrimSpace(res.Recorder.Body.Bytes()))
+	}
+	return res
+}
+
+// ExpectPattern checks the routing pattern matched by the request, as
+// reported by chi.Context.RoutePattern.
+func (res *Response) ExpectPattern(pattern string) *Response {
+	res.t.Helper()
+	if got := res.RouteContext.RoutePattern(); got != pattern {
+		res.t.Errorf("chitest: %s: expected route pattern %q, got %q", res, pattern, got)
+	}
+	return res
+}
+
+// ExpectRoutePatterns checks the routing patterns matched by each router
+// the request went through, as in chi.Context.RoutePatterns.
+func (res *Response) ExpectRoutePatterns(patterns ...string) *Response {
+	res.t.Helper()
+	if got := res.RouteContext.RoutePatterns; !slices.Equal(got, patterns) {
+		res.t.Errorf("chitest: %s: expected route patterns %q, got %q", res, patterns, got)
+	}
+	return res
+}
+
+// ExpectParam checks the value of a URL param of the request.
+func (res *Response) ExpectParam(key, value string) *Response {
+	res.t.Helper()
+
+	i := slices.Index(res.RouteContext.URLParams.Keys, key)
+	if i < 0 {
+		res.t.Errorf("chitest: %s: expected url param %q, got none", res, key)
+		return res
+	}
+	if got := res.RouteContext.URLParam(key); got != value {
+		res.t.Errorf("chitest: %s: expected url param %q to be %q, got %q", res, key, value, got)
+	}
+	return res
+}
+
+// ExpectMiddlewares checks the names of the middlewares wrapping the route
+// matched by the request, in order. See ExpectMiddlewares.
+func (res *Response) ExpectMiddlewares(names ...string) *Response {
+	res.t.Helper()
+
+	routes, ok := res.handler.(chi.Routes)
+	if !ok {
+		res.t.Errorf("chitest: %s: expected the handler to be a chi router, got %T", res, res.handler)
+		return res
+	}
+	ExpectMiddlewares(res.t, routes, res.Request.Method, res.RouteContext.RoutePattern(), names...)
+	return res
+}
+
+// String returns the method and target of the request, for error messages.
+func (res *Response) String() string {
+	return res.Request.Method + " " + res.Request.URL.RequestURI()
+}
+
+// ExpectMiddlewares checks the names of the middlewares wrapping the route
+// with the method and pattern registered on `r`, in order, as reported by
+// chi.Walk. Name the middlewares with chi.Named, for example:
+//
+//	r.Use(chi.Named("auth", Auth))
+//	r.With(chi.Named("ratelimit", RateLimit)).Get("/users", listUsers)
+//
+//	chitest.ExpectMiddlewares(t, r, "GET", "/users", "auth", "ratelimit")
+func ExpectMiddlewares(t testing.TB, r chi.Routes, method, pattern string, names ...string) {
+	t.Helper()
+
+	got, ok := middlewareNames(r, method, pattern)
+	if !ok {
+		t.Errorf("chitest: expected a %s %s route, got none", method, pattern)
+		return
+	}
+	if !slices.Equal(got, names) {
+		t.Errorf("chitest: %s %s: expected middlewares %q, got %q", method, pattern, names, got)
+	}
+}
+
+// middlewareNames returns the names of the middlewares of the route with
+// the method and pattern.
+func middlewareNames(r chi.Routes, method, pattern string) ([]string, bool) {
+	var (
+		names []string
+		found bool
+	)
+	chi.Walk(r, func(m, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if m == method && trimPattern(route) == trimPattern(pattern) {
+			names, found = chi.Middlewares(middlewares).Names(), true
+		}
+		return nil
+	})
+	return names, found
+}
+
+// trimPattern trims the trailing slash of a pattern, which RoutePattern
+// leaves out for the root route of a mounted sub-router.
+func trimPattern(pattern string) string {
+	if pattern == "/" {
+		return pattern
+	}
+	return strings.TrimSuffix(pattern, "/")
+}
@@ -0,0 +1,128 @@
+package chitest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// recordT is a testing.TB recording the reported errors.
+type recordT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordT) Helper() {}
+
+func (t *recordT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func testRouter() chi.Router {
+	mw := func(next http.Handler) http.Handler { return next }
+
+	r := chi.NewRouter()
+	r.Use(chi.Named("requestID", mw))
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("root"))
+	})
+
+	r.Route("/users", func(r chi.Router) {
+		r.Use(chi.Named("auth", mw))
+		r.With(chi.Named("ratelimit", mw)).Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"id": %q, "name": "Gopher"}`, chi.URLParam(r, "id"))
+		})
+		r.Post("/", func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, "%s %s", r.Header.Get("Content-Type"), body)
+		})
+	})
+	return r
+}
+
+func TestDo(t *testing.T) {
+	r := testRouter()
+
+	Do(t, r, "GET", "/").
+		ExpectStatus(http.StatusOK).
+		ExpectBody("root").
+		ExpectPattern("/").
+		ExpectMiddlewares("requestID")
+
+	Do(t, r, "GET", "/users/1").
+		ExpectStatus(http.StatusOK).
+		ExpectHeader("Content-Type", "application/json").
+		ExpectJSON(map[string]any{"name": "Gopher", "id": "1"}).
+		ExpectPattern("/users/{id}").
+		ExpectRoutePatterns("/users/*", "/{id}").
+		ExpectParam("id", "1").
+		ExpectMiddlewares("requestID", "auth", "ratelimit")
+
+	NewRequest("POST", "/users").
+		JSON(map[string]string{"name": "Gopher"}).
+		Do(t, r).
+		ExpectStatus(http.StatusCreated).
+		ExpectBody(`application/json {"name":"Gopher"}`).
+		ExpectPattern("/users").
+		ExpectMiddlewares("requestID", "auth")
+
+	Do(t, r, "GET", "/nope").
+		ExpectStatus(http.StatusNotFound).
+		ExpectBodyContains("not found")
+}
+
+func TestDoFailures(t *testing.T) {
+	rt := &recordT{TB: t}
+
+	Do(rt, testRouter(), "GET", "/users/1").
+		ExpectStatus(http.StatusNotFound).
+		ExpectHeader("Content-Type", "text/plain").
+		ExpectBody("nope").
+		ExpectBodyContains("nope").
+		ExpectJSON(map[string]any{"id": "2"}).
+		ExpectPattern("/users/{userID}").
+		ExpectRoutePatterns("/users/{id}").
+		ExpectParam("id", "2").
+		ExpectParam("name", "Gopher").
+		ExpectMiddlewares("auth")
+
+	want := []string{
+		`chitest: GET /users/1: expected status 404, got 200`,
+		`chitest: GET /users/1: expected header Content-Type "text/plain", got "application/json"`,
+		`chitest: GET /users/1: expected body "nope", got "{\"id\": \"1\", \"name\": \"Gopher\"}"`,
+		`chitest: GET /users/1: expected body containing "nope", got "{\"id\": \"1\", \"name\": \"Gopher\"}"`,
+		`chitest: GET /users/1: expected JSON body {"id":"2"}, got {"id": "1", "name": "Gopher"}`,
+		`chitest: GET /users/1: expected route pattern "/users/{userID}", got "/users/{id}"`,
+		`chitest: GET /users/1: expected route patterns ["/users/{id}"], got ["/users/*" "/{id}"]`,
+		`chitest: GET /users/1: expected url param "id" to be "2", got "1"`,
+		`chitest: GET /users/1: expected url param "name", got none`,
+		`chitest: GET /users/{id}: expected middlewares ["auth"], got ["requestID" "auth" "ratelimit"]`,
+	}
+	if len(rt.errors) != len(want) {
+		t.Fatalf("expected %d errors, got:\n%s", len(want), strings.Join(rt.errors, "\n"))
+	}
+	for i := range want {
+		if rt.errors[i] != want[i] {
+			t.Errorf("unexpected error:\n%s\nexpected:\n%s", rt.errors[i], want[i])
+		}
+	}
+}
+
+func TestExpectMiddlewares(t *testing.T) {
+	r := testRouter()
+
+	ExpectMiddlewares(t, r, "GET", "/users/{id}", "requestID", "auth", "ratelimit")
+	ExpectMiddlewares(t, r, "POST", "/users/", "requestID", "auth")
+
+	rt := &recordT{TB: t}
+	ExpectMiddlewares(rt, r, "DELETE", "/users/{id}")
+	if len(rt.errors) != 1 || rt.errors[0] != "chitest: expected a DELETE /users/{id} route, got none" {
+		t.Fatalf("unexpected errors: %q", rt.errors)
+	}
+}
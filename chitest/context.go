@@ -0,0 +1,75 @@
+package chitest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// WithURLParams returns a shallow copy of `r` with the URL params, given as
+// key and value pairs, added to its routing context. The params are also
+// set as the path values of the request, as the router does, so they are
+// returned by both chi.URLParam and r.PathValue. It's meant to unit-test
+// handlers without a router, for example:
+//
+//	r := chitest.WithURLParams(httptest.NewRequest("GET", "/users/1", nil), "id", "1")
+//	getUser(w, r)
+//
+// WithURLParams panics if given an odd number of arguments.
+func WithURLParams(r *http.Request, kv ...string) *http.Request {
+	if len(kv)%2 != 0 {
+		panic("chitest: WithURLParams expects key and value pairs")
+	}
+
+	rctx := routeContext(r)
+	for i := 0; i < len(kv); i += 2 {
+		rctx.URLParams.Add(kv[i], kv[i+1])
+	}
+	return withRouteContext(r, rctx)
+}
+
+// WithRoutePattern returns a shallow copy of `r` with the routing context
+// of a router serving the request with the routing `pattern`: the URL
+// params are parsed from the request path, and the pattern is reported by
+// chi.Context.RoutePattern and r.Pattern. For example:
+//
+//	r := chitest.WithRoutePattern(httptest.NewRequest("GET", "/users/1", nil), "/users/{id}")
+//	getUser(w, r) // chi.URLParam(r, "id") == "1"
+//
+// WithRoutePattern panics if the pattern doesn't match the request path.
+func WithRoutePattern(r *http.Request, pattern string) *http.Request {
+	mx := chi.NewRouter()
+	mx.Handle(pattern, http.NotFoundHandler())
+
+	path := r.URL.RawPath
+	if path == "" {
+		path = r.URL.Path
+	}
+
+	rctx := routeContext(r)
+	if !mx.Match(rctx, http.MethodGet, path) {
+		panic("chitest: routing pattern '" + pattern + "' doesn't match the request path '" + path + "'")
+	}
+	return withRouteContext(r, rctx)
+}
+
+// routeContext returns a copy of the routing context of the request, or a
+// new one.
+func routeContext(r *http.Request) *chi.Context {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return rctx.Clone()
+	}
+	return chi.NewRouteContext()
+}
+
+// withRouteContext returns a shallow copy of the request with the routing
+// context, and the path values and pattern set from it.
+func withRouteContext(r *http.Request, rctx *chi.Context) *http.Request {
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	for i, key := range rctx.URLParams.Keys {
+		r.SetPathValue(key, rctx.URLParams.Values[i])
+	}
+	r.Pattern = rctx.RoutePattern()
+	return r
+}
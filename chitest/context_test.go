@@ -0,0 +1,85 @@
+package chitest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestWithURLParams(t *testing.T) {
+	r := httptest.NewRequest("GET", "/users/1/posts/2", nil)
+	r = WithURLParams(r, "id", "1")
+	r = WithURLParams(r, "post", "2")
+
+	if got := chi.URLParam(r, "id"); got != "1" {
+		t.Fatalf("expected url param id to be 1, got %q", got)
+	}
+	if got := chi.URLParam(r, "post"); got != "2" {
+		t.Fatalf("expected url param post to be 2, got %q", got)
+	}
+	if got := r.PathValue("id"); got != "1" {
+		t.Fatalf("expected path value id to be 1, got %q", got)
+	}
+	if got := r.PathValue("post"); got != "2" {
+		t.Fatalf("expected path value post to be 2, got %q", got)
+	}
+}
+
+func TestWithURLParamsOddArguments(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+	}()
+	WithURLParams(httptest.NewRequest("GET", "/", nil), "id")
+}
+
+func TestWithRoutePattern(t *testing.T) {
+	original := httptest.NewRequest("GET", "/users/1/files/docs/a.txt", nil)
+	r := WithRoutePattern(original, "/users/{id:[0-9]+}/files/*")
+
+	rctx := chi.RouteContext(r.Context())
+	if got := rctx.RoutePattern(); got != "/users/{id:[0-9]+}/files/*" {
+		t.Fatalf("unexpected route pattern %q", got)
+	}
+	if r.Pattern != "/users/{id:[0-9]+}/files/*" {
+		t.Fatalf("unexpected request pattern %q", r.Pattern)
+	}
+	if got := chi.URLParam(r, "id"); got != "1" {
+		t.Fatalf("expected url param id to be 1, got %q", got)
+	}
+	if got := r.PathValue("*"); got != "docs/a.txt" {
+		t.Fatalf("expected path value * to be docs/a.txt, got %q", got)
+	}
+	if chi.RouteContext(original.Context()) != nil {
+		t.Fatal("expected the original request to be left untouched")
+	}
+
+	// The handler sees the same values as when served by a router.
+	var fromRouter, fromTest string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		rctx := chi.RouteContext(r.Context())
+		fromTest = rctx.RoutePattern() + " " + chi.URLParam(r, "id") + " " + r.PathValue("id")
+	}
+	router := chi.NewRouter()
+	router.Get("/users/{id:[0-9]+}/files/*", func(w http.ResponseWriter, r *http.Request) {
+		rctx := chi.RouteContext(r.Context())
+		fromRouter = rctx.RoutePattern() + " " + chi.URLParam(r, "id") + " " + r.PathValue("id")
+	})
+	router.ServeHTTP(httptest.NewRecorder(), original)
+	handler(httptest.NewRecorder(), r)
+	if fromRouter != fromTest {
+		t.Fatalf("expected %q, got %q", fromRouter, fromTest)
+	}
+}
+
+func TestWithRoutePatternMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+	}()
+	WithRoutePattern(httptest.NewRequest("GET", "/users/abc", nil), "/users/{id:[0-9]+}")
+}
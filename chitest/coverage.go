@@ -0,0 +1,168 @@
+package chitest
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CoverageRecorder is a chi.Router recording the routes served by the
+// router it wraps, see Coverage.
+type CoverageRecorder struct {
+	chi.Router
+
+	mu   sync.Mutex
+	hits map[string]int
+}
+
+// Coverage returns a router serving requests with the root router `r`, and
+// recording the method and route pattern of every request, to report the
+// routes left uncovered by a test suite. For example:
+//
+//	var router *chitest.CoverageRecorder
+//
+//	func TestMain(m *testing.M) {
+//		router = chitest.Coverage(NewRouter())
+//		code := m.Run()
+//		fmt.Print(router.Report())
+//		os.Exit(code)
+//	}
+//
+// Routes are identified by their full routing pattern, as reported by
+// chi.Walk and chi.Context.RoutePattern.
+func Coverage(r chi.Router) *CoverageRecorder {
+	return &CoverageRecorder{Router: r, hits: map[string]int{}}
+}
+
+// ServeHTTP serves the request with the wrapped router, and records the
+// route it matched.
+func (c *CoverageRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		// Use a routing context of our own, so the pattern can be read once
+		// the request is done.
+		rctx = chi.NewRouteContext()
+		rctx.Routes = c
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	c.Router.ServeHTTP(w, r)
+
+	pattern := rctx.RoutePattern()
+	if pattern == "" {
+		return
+	}
+	method := rctx.RouteMethod
+	if method == "" {
+		method = r.Method
+	}
+
+	c.mu.Lock()
+	c.hits[coverageKey(method, pattern)]++
+	c.mu.Unlock()
+}
+
+// Report returns the coverage of the routes of the wrapped router.
+func (c *CoverageRecorder) Report() *CoverageReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := &CoverageReport{Routes: []RouteCoverage{}}
+	seen := map[string]bool{}
+	chi.Walk(c.Router, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		key := coverageKey(method, route)
+		if seen[key] {
+			return nil
+		}
+		seen[key] = true
+
+		rc := RouteCoverage{Method: method, Pattern: route, Hits: c.hits[key]}
+		report.Routes = append(report.Routes, rc)
+		report.Total++
+		if rc.Hits > 0 {
+			report.Covered++
+		}
+		return nil
+	})
+
+	slices.SortFunc(report.Routes, func(a, b RouteCoverage) int {
+		return cmp.Or(strings.Compare(a.Pattern, b.Pattern), strings.Compare(a.Method, b.Method))
+	})
+	return report
+}
+
+// ExpectCovered reports every route left uncovered as an error to `t`.
+func (c *CoverageRecorder) ExpectCovered(t testing.TB) {
+	t.Helper()
+	for _, rc := range c.Report().Uncovered() {
+		t.Errorf("chitest: route %s %s is not covered", rc.Method, rc.Pattern)
+	}
+}
+
+// CoverageReport is the route coverage of a router, see CoverageRecorder.
+type CoverageReport struct {
+	// Covered is the number of routes served at least once.
+	Covered int `json:"covered"`
+
+	// Total is the number of routes of the router.
+	Total int `json:"total"`
+
+	// Routes are all routes of the router, sorted by pattern and method.
+	Routes []RouteCoverage `json:"routes"`
+}
+
+// RouteCoverage is the coverage of a route.
+type RouteCoverage struct {
+	Method  string `json:"method"`
+	Pattern string `json:"pattern"`
+	Hits    int    `json:"hits"`
+}
+
+// Uncovered returns the routes never served.
+func (rep *CoverageReport) Uncovered() []RouteCoverage {
+	var routes []RouteCoverage
+	for _, rc := range rep.Routes {
+		if rc.Hits == 0 {
+			routes = append(routes, rc)
+		}
+	}
+	return routes
+}
+
+// String returns a text report of the coverage, listing the uncovered
+// routes.
+func (rep *CoverageReport) String() string {
+	var b strings.Builder
+	percent := 100.0
+	if rep.Total > 0 {
+		percent = float64(rep.Covered) * 100 / float64(rep.Total)
+	}
+	fmt.Fprintf(&b, "route coverage: %d/%d routes (%.1f%%)\n", rep.Covered, rep.Total, percent)
+
+	if uncovered := rep.Uncovered(); len(uncovered) > 0 {
+		b.WriteString("uncovered routes:\n")
+		for _, rc := range uncovered {
+			fmt.Fprintf(&b, "\t%s %s\n", rc.Method, rc.Pattern)
+		}
+	}
+	return b.String()
+}
+
+// JSON returns the report encoded as indented JSON.
+func (rep *CoverageReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(rep, "", "  ")
+}
+
+// coverageKey returns the key of a route, ignoring the trailing slash
+// RoutePattern leaves out for the root route of a mounted sub-router.
+func coverageKey(method, pattern string) string {
+	return method + " " + trimPattern(pattern)
+}
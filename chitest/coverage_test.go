@@ -0,0 +1,75 @@
+package chitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestCoverage(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := chi.NewRouter()
+	r.Get("/", handler)
+	r.Route("/users", func(r chi.Router) {
+		r.Get("/", handler)
+		r.Get("/{id}", handler)
+		r.Delete("/{id}", handler)
+	})
+
+	cov := Coverage(r)
+	Do(t, cov, "GET", "/users/1").ExpectStatus(http.StatusOK)
+	Do(t, cov, "GET", "/users/2").ExpectPattern("/users/{id}")
+	Do(t, cov, "GET", "/users").ExpectStatus(http.StatusOK)
+	Do(t, cov, "GET", "/nope").ExpectStatus(http.StatusNotFound)
+	cov.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/users/1", nil))
+
+	report := cov.Report()
+	want := []RouteCoverage{
+		{"GET", "/", 0},
+		{"GET", "/users/", 1},
+		{"DELETE", "/users/{id}", 0},
+		{"GET", "/users/{id}", 2},
+	}
+	if len(report.Routes) != len(want) {
+		t.Fatalf("expected routes %v, got %v", want, report.Routes)
+	}
+	for i := range want {
+		if report.Routes[i] != want[i] {
+			t.Fatalf("expected routes %v, got %v", want, report.Routes)
+		}
+	}
+	if report.Covered != 2 || report.Total != 4 {
+		t.Fatalf("expected 2/4 routes covered, got %d/%d", report.Covered, report.Total)
+	}
+
+	text := `route coverage: 2/4 routes (50.0%)
+uncovered routes:
+	GET /
+	DELETE /users/{id}
+`
+	if s := report.String(); s != text {
+		t.Fatalf("unexpected text report:\n%s\nexpected:\n%s", s, text)
+	}
+
+	b, err := report.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded CoverageReport
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Covered != 2 || decoded.Total != 4 || len(decoded.Routes) != 4 {
+		t.Fatalf("unexpected JSON report:\n%s", b)
+	}
+
+	rt := &recordT{TB: t}
+	cov.ExpectCovered(rt)
+	if len(rt.errors) != 2 || rt.errors[0] != "chitest: route GET / is not covered" {
+		t.Fatalf("unexpected errors: %q", rt.errors)
+	}
+}
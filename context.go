@@ -2,6 +2,7 @@ package chi
 
 import (
 	"context"
+	"iter"
 	"net/http"
 	"slices"
 	"strings"
@@ -79,6 +80,26 @@ type Context struct {
 
 	methodsAllowed   []methodTyp // allowed methods in case of a 405
 	methodNotAllowed bool
+
+	// The endpoint matched by the last router searched for the request,
+	// and all endpoints of its node.
+	endpoint  *endpoint
+	endpoints endpoints
+
+	// Routers traversed by the request, see Context#Route.
+	routers []Routes
+
+	// Middlewares registered with UseMatched by parent routers, pending
+	// until the routing reaches the final endpoint.
+	matchedMiddlewares []func(http.Handler) http.Handler
+
+	// Limits of the routers traversed by the request, see Mux#Limits.
+	limits RouteLimits
+
+	// Fallback handlers handed down by a parent router to a mounted
+	// sub-router, see Mux#Mount.
+	notFoundHandler         http.HandlerFunc
+	methodNotAllowedHandler http.HandlerFunc
 }
 
 // Reset a routing context to its initial state.
@@ -95,6 +116,13 @@ func (x *Context) Reset() {
 	x.routeParams.Values = x.routeParams.Values[:0]
 	x.methodNotAllowed = false
 	x.methodsAllowed = x.methodsAllowed[:0]
+	x.endpoint = nil
+	x.endpoints = nil
+	x.routers = x.routers[:0]
+	x.matchedMiddlewares = x.matchedMiddlewares[:0]
+	x.limits = RouteLimits{}
+	x.notFoundHandler = nil
+	x.methodNotAllowedHandler = nil
 	x.parentCtx = nil
 }
 
@@ -117,6 +145,8 @@ func (x *Context) Clone() *Context {
 	clone.routeParams.Values = slices.Clone(x.routeParams.Values)
 
 	clone.RoutePatterns = slices.Clone(x.RoutePatterns)
+	clone.routers = slices.Clone(x.routers)
+	clone.matchedMiddlewares = slices.Clone(x.matchedMiddlewares)
 	clone.methodsAllowed = slices.Clone(x.methodsAllowed)
 
 	return &clone
@@ -133,6 +163,51 @@ func (x *Context) URLParam(key string) string {
 	return ""
 }
 
+// Params returns an iterator over the URL params of the request, in the
+// order they were matched. A key captured by several routers is yielded
+// once, with the value of the innermost router, like URLParam returns.
+//
+// The catch-all "*" param is left out when empty, as it is for requests
+// routed through a mounted sub-router.
+func (x *Context) Params() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		if x == nil {
+			return
+		}
+		for i, key := range x.URLParams.Keys {
+			if slices.Contains(x.URLParams.Keys[:i], key) {
+				continue
+			}
+			value := x.URLParam(key)
+			if key == "*" && value == "" {
+				continue
+			}
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+// ParamsMap returns the URL params of the request, keyed by name. See Params.
+func (x *Context) ParamsMap() map[string]string {
+	params := map[string]string{}
+	for key, value := range x.Params() {
+		params[key] = value
+	}
+	return params
+}
+
+// ParamNames returns the names of the URL params of the request, in the
+// order they were matched, without duplicates. See Params.
+func (x *Context) ParamNames() []string {
+	var names []string
+	for key := range x.Params() {
+		names = append(names, key)
+	}
+	return names
+}
+
 // RoutePattern builds the routing pattern string for the particular
 // request, at the particular point during routing. This means, the value
 // will change throughout the execution of a request in a router. That is
@@ -160,6 +235,88 @@ func (x *Context) RoutePattern() string {
 	return routePattern
 }
 
+// RouteMetadata returns the Metadata carried by the handler of the matched
+// route, see WithMetadata. Like RoutePattern, it's only final once routing
+// reached the endpoint, as in middlewares registered with Mux#UseMatched.
+func (x *Context) RouteMetadata() Metadata {
+	if x == nil || x.endpoint == nil {
+		return nil
+	}
+	return HandlerMetadata(x.endpoint.handler)
+}
+
+// RouteMatch describes the route matched by a request, see Context#Route.
+type RouteMatch struct {
+	// Matched reports whether a route handler was found for the request,
+	// as opposed to the request being handled as not found or method not
+	// allowed.
+	Matched bool
+
+	// Method is the routing method of the request.
+	Method string
+
+	// Pattern is the full routing pattern matched, as returned by
+	// RoutePattern.
+	Pattern string
+
+	// ParamKeys are the URL param keys of the pattern, in order.
+	ParamKeys []string
+
+	// AllowedMethods are the methods with a route on the matched path,
+	// which for a method not allowed request are the methods it may use
+	// instead.
+	AllowedMethods []string
+
+	// Routers are the routers traversed by the request, from the root
+	// router to the one the route was registered on.
+	Routers []Routes
+
+	// Metadata is the metadata of the route handler, see WithMetadata.
+	Metadata Metadata
+}
+
+// Route returns a description of the route matched by the request. Like
+// RoutePattern, it's only final once routing reached the endpoint, so use
+// it after calling the next handler in a middleware, or in a route handler,
+// including the NotFound and MethodNotAllowed handlers.
+func (x *Context) Route() *RouteMatch {
+	if x == nil {
+		return nil
+	}
+
+	route := &RouteMatch{
+		Method:  x.RouteMethod,
+		Pattern: x.RoutePattern(),
+		Routers: slices.Clone(x.routers),
+	}
+	for _, p := range patParams(route.Pattern) {
+		route.ParamKeys = append(route.ParamKeys, p.Name)
+	}
+
+	var methods []methodTyp
+	if x.endpoint != nil && !x.endpoint.stub {
+		route.Matched = true
+		route.Metadata = HandlerMetadata(x.endpoint.handler)
+		for mt, e := range x.endpoints {
+			if mt != mALL && mt != mSTUB && e.handler != nil {
+				methods = append(methods, mt)
+			}
+		}
+	} else if x.methodNotAllowed {
+		methods = slices.Clone(x.methodsAllowed)
+	}
+	slices.Sort(methods)
+	var mx *Mux
+	if n := len(x.routers); n > 0 {
+		mx, _ = x.routers[n-1].(*Mux)
+	}
+	for _, mt := range methods {
+		route.AllowedMethods = append(route.AllowedMethods, mx.methodName(mt))
+	}
+
+	return route
+}
+
 // replaceWildcards takes a route pattern and replaces all occurrences of
 // "/*/" with "/". It iteratively runs until no wildcards remain to
 // correctly handle consecutive wildcards.
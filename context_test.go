@@ -2,6 +2,10 @@ package chi
 
 import (
 	"context"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"testing"
 )
 
@@ -153,3 +157,128 @@ func TestContext_Clone(t *testing.T) {
 		t.Fatalf("clone parentCtx should be detached, got %v", clone.parentCtx)
 	}
 }
+
+func TestContextParams(t *testing.T) {
+	x := NewRouteContext()
+	x.URLParams.Add("id", "outer")
+	x.URLParams.Add("post", "2")
+	x.URLParams.Add("id", "inner")
+	x.URLParams.Add("*", "a/b")
+
+	var keys, values []string
+	for key, value := range x.Params() {
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	if want := []string{"id", "post", "*"}; !slices.Equal(keys, want) {
+		t.Fatalf("expected keys %v, got %v", want, keys)
+	}
+	if want := []string{"inner", "2", "a/b"}; !slices.Equal(values, want) {
+		t.Fatalf("expected values %v, got %v", want, values)
+	}
+
+	if names := x.ParamNames(); !slices.Equal(names, keys) {
+		t.Fatalf("expected names %v, got %v", keys, names)
+	}
+	if params := x.ParamsMap(); !maps.Equal(params, map[string]string{"id": "inner", "post": "2", "*": "a/b"}) {
+		t.Fatalf("unexpected params map %v", params)
+	}
+
+	// Stop early
+	for key := range x.Params() {
+		if key != "id" {
+			t.Fatalf("expected iteration to stop after the first param, got %q", key)
+		}
+		break
+	}
+
+	var empty *Context
+	if len(empty.ParamsMap()) != 0 || empty.ParamNames() != nil {
+		t.Fatal("expected no params for a nil context")
+	}
+}
+
+func TestContextParamsShadowedAcrossRouters(t *testing.T) {
+	var params map[string]string
+	var names []string
+
+	r := NewRouter()
+	r.Route("/{org}/{id}", func(r Router) {
+		r.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+			rctx := RouteContext(r.Context())
+			params, names = rctx.ParamsMap(), rctx.ParamNames()
+		})
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/acme/outer/items/inner", nil))
+
+	if want := map[string]string{"org": "acme", "id": "inner"}; !maps.Equal(params, want) {
+		t.Fatalf("expected params %v, got %v", want, params)
+	}
+	if want := []string{"org", "id"}; !slices.Equal(names, want) {
+		t.Fatalf("expected names %v, got %v", want, names)
+	}
+}
+
+func TestContextRoute(t *testing.T) {
+	var route *RouteMatch
+	record := func(w http.ResponseWriter, r *http.Request) {
+		route = RouteContext(r.Context()).Route()
+	}
+
+	users := NewRouter()
+	users.Method("GET", "/{id}", WithMetadata(http.HandlerFunc(record), Metadata{"summary": "get user"}))
+	users.Put("/{id}", record)
+	users.MethodNotAllowed(record)
+	users.NotFound(record)
+
+	r := NewRouter()
+	r.Get("/", record)
+	r.Mount("/orgs/{org}/users", users)
+	r.Mount("/static", http.HandlerFunc(record))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/orgs/acme/users/1", nil))
+	if !route.Matched || route.Method != "GET" || route.Pattern != "/orgs/{org}/users/{id}" {
+		t.Fatalf("unexpected route %+v", route)
+	}
+	if !slices.Equal(route.ParamKeys, []string{"org", "id"}) {
+		t.Fatalf("unexpected param keys %v", route.ParamKeys)
+	}
+	if !slices.Equal(route.AllowedMethods, []string{"GET", "PUT"}) {
+		t.Fatalf("unexpected allowed methods %v", route.AllowedMethods)
+	}
+	if len(route.Routers) != 2 || route.Routers[0] != r || route.Routers[1] != users {
+		t.Fatalf("unexpected routers %v", route.Routers)
+	}
+	if route.Metadata["summary"] != "get user" {
+		t.Fatalf("unexpected metadata %v", route.Metadata)
+	}
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("DELETE", "/orgs/acme/users/1", nil))
+	if route.Matched || route.Metadata != nil || !slices.Equal(route.AllowedMethods, []string{"GET", "PUT"}) {
+		t.Fatalf("unexpected route for a method not allowed %+v", route)
+	}
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/orgs/acme/users/1/nope", nil))
+	if route.Matched || route.AllowedMethods != nil || len(route.Routers) != 2 {
+		t.Fatalf("unexpected route for a route not found %+v", route)
+	}
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if !route.Matched || route.Pattern != "/" || route.ParamKeys != nil || len(route.Routers) != 1 {
+		t.Fatalf("unexpected route %+v", route)
+	}
+
+	// A handler other than a Router is the final endpoint of its mount.
+	for _, path := range []string{"/static", "/static/", "/static/a.css"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+		if !route.Matched || len(route.Routers) != 1 {
+			t.Fatalf("unexpected route for %s %+v", path, route)
+		}
+	}
+
+	var empty *Context
+	if empty.Route() != nil {
+		t.Fatal("expected no route for a nil context")
+	}
+}
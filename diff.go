@@ -0,0 +1,177 @@
+package chi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RoutesDiff is the difference between the routes of two routers, as
+// returned by DiffRoutes.
+type RoutesDiff struct {
+	// Added are the routes only found in the new router.
+	Added []RouteInfo
+
+	// Removed are the routes only found in the old router.
+	Removed []RouteInfo
+
+	// Changed are the routes found in both routers, with differences.
+	Changed []RouteChange
+}
+
+// RouteChange describes the differences of a route between two routers.
+type RouteChange struct {
+	Old RouteInfo
+	New RouteInfo
+
+	// Changes describes each difference, like a renamed param.
+	Changes []string
+
+	// Breaking reports whether a request served by the old route may no
+	// longer be served by the new one.
+	Breaking bool
+}
+
+// DiffRoutes compares the routes of the `old` and `new` routers, including
+// their mounted sub-routers, for example to fail a build on breaking API
+// changes:
+//
+//	if diff := chi.DiffRoutes(publishedRouter(), newRouter()); diff.Breaking() {
+//		t.Fatalf("breaking API changes:\n%s", diff)
+//	}
+//
+// Routes are matched by method and pattern, where patterns only differing
+// by the names or regexps of their params are the same route. Removing a
+// route, or adding or changing the regexp of a param, is a breaking change.
+// Renaming a param, removing its regexp and changing the middleware stack
+// are reported as non-breaking changes.
+func DiffRoutes(old, new Routes) *RoutesDiff {
+	oldRoutes, newRoutes := routeInfos(old), routeInfos(new)
+	diff := &RoutesDiff{}
+
+	// Pair up identical patterns first, then patterns of the same shape,
+	// each new route being paired up once.
+	matched := make([]bool, len(newRoutes))
+	pair := func(o RouteInfo, same func(n RouteInfo) bool) bool {
+		for i, n := range newRoutes {
+			if !matched[i] && n.Method == o.Method && same(n) {
+				matched[i] = true
+				diff.compare(o, n)
+				return true
+			}
+		}
+		return false
+	}
+
+	var unmatched []RouteInfo
+	for _, o := range oldRoutes {
+		if !pair(o, func(n RouteInfo) bool { return n.Pattern == o.Pattern }) {
+			unmatched = append(unmatched, o)
+		}
+	}
+	for _, o := range unmatched {
+		shape := patShape(o.Pattern)
+		if !pair(o, func(n RouteInfo) bool { return patShape(n.Pattern) == shape }) {
+			diff.Removed = append(diff.Removed, o)
+		}
+	}
+	for i, n := range newRoutes {
+		if !matched[i] {
+			diff.Added = append(diff.Added, n)
+		}
+	}
+
+	return diff
+}
+
+// compare records the differences between two routes matching the same
+// requests, if any.
+func (d *RoutesDiff) compare(o, n RouteInfo) {
+	change := RouteChange{Old: o, New: n}
+
+	for i := range min(len(o.Params), len(n.Params)) {
+		op, np := o.Params[i], n.Params[i]
+		if op.Name != np.Name {
+			change.Changes = append(change.Changes, fmt.Sprintf("param %q renamed to %q", op.Name, np.Name))
+		}
+		switch {
+		case op.Regexp == np.Regexp:
+		case op.Regexp == "":
+			change.Changes = append(change.Changes, fmt.Sprintf("param %q constrained to %s", np.Name, np.Regexp))
+			change.Breaking = true
+		case np.Regexp == "":
+			change.Changes = append(change.Changes, fmt.Sprintf("param %q no longer constrained to %s", np.Name, op.Regexp))
+		default:
+			change.Changes = append(change.Changes, fmt.Sprintf("param %q constraint changed from %s to %s", np.Name, op.Regexp, np.Regexp))
+			change.Breaking = true
+		}
+	}
+
+	if !slices.Equal(o.Middlewares, n.Middlewares) {
+		change.Changes = append(change.Changes, fmt.Sprintf("middlewares changed from [%s] to [%s]",
+			strings.Join(o.Middlewares, " "), strings.Join(n.Middlewares, " ")))
+	}
+
+	if len(change.Changes) > 0 {
+		d.Changed = append(d.Changed, change)
+	}
+}
+
+// Breaking reports whether any route was removed, or changed in a breaking
+// way.
+func (d *RoutesDiff) Breaking() bool {
+	if len(d.Removed) > 0 {
+		return true
+	}
+	for _, c := range d.Changed {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Empty reports whether both routers have the same routes.
+func (d *RoutesDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String returns the differences one per line, prefixed with "+" for added
+// routes, "-" for removed routes and "~" for changed routes.
+func (d *RoutesDiff) String() string {
+	var b strings.Builder
+	for _, rt := range d.Removed {
+		fmt.Fprintf(&b, "- %s %s (breaking)\n", rt.Method, rt.Pattern)
+	}
+	for _, rt := range d.Added {
+		fmt.Fprintf(&b, "+ %s %s\n", rt.Method, rt.Pattern)
+	}
+	for _, c := range d.Changed {
+		breaking := ""
+		if c.Breaking {
+			breaking = " (breaking)"
+		}
+		fmt.Fprintf(&b, "~ %s %s: %s%s\n", c.New.Method, c.New.Pattern, strings.Join(c.Changes, ", "), breaking)
+	}
+	return b.String()
+}
+
+// patShape returns the routing pattern with the names and regexps of its
+// params left out, as in "/users/{}/*".
+func patShape(pattern string) string {
+	var b strings.Builder
+	for pat := pattern; ; {
+		ptyp, _, _, _, ps, pe := patNextSegment(pat)
+		if ptyp == ntStatic {
+			b.WriteString(pat)
+			return b.String()
+		}
+		b.WriteString(pat[:ps])
+		if ptyp == ntCatchAll {
+			b.WriteString("*")
+		} else {
+			b.WriteString("{}")
+		}
+		pat = pat[pe:]
+	}
+}
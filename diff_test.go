@@ -0,0 +1,128 @@
+package chi
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDiffRoutes(t *testing.T) {
+	mw := func(next http.Handler) http.Handler { return next }
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	oldRouter := NewRouter()
+	oldRouter.Get("/users", handler)
+	oldRouter.Get("/users/{id}", handler)
+	oldRouter.Get("/orgs/{org:[a-z]+}", handler)
+	oldRouter.Get("/teams/{team:[a-z]+}", handler)
+	oldRouter.Route("/admin", func(r Router) {
+		r.Get("/stats", handler)
+		r.Delete("/cache", handler)
+	})
+
+	newRouter := NewRouter()
+	newRouter.Get("/users", handler)
+	newRouter.Post("/users", handler)
+	newRouter.Get("/users/{userID:[0-9]+}", handler)
+	newRouter.Get("/orgs/{org}", handler)
+	newRouter.Get("/teams/{team:[a-z0-9]+}", handler)
+	newRouter.Route("/admin", func(r Router) {
+		r.Use(Named("auth", mw))
+		r.Get("/stats", handler)
+	})
+
+	diff := DiffRoutes(oldRouter, newRouter)
+
+	if len(diff.Added) != 1 || diff.Added[0].Method != "POST" || diff.Added[0].Pattern != "/users" {
+		t.Fatalf("expected POST /users to be added, got %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Method != "DELETE" || diff.Removed[0].Pattern != "/admin/cache" {
+		t.Fatalf("expected DELETE /admin/cache to be removed, got %+v", diff.Removed)
+	}
+	if !diff.Breaking() {
+		t.Fatal("expected the diff to be breaking")
+	}
+
+	want := map[string]struct {
+		changes  string
+		breaking bool
+	}{
+		"/admin/stats":            {`middlewares changed from [] to [auth]`, false},
+		"/orgs/{org}":             {`param "org" no longer constrained to ^[a-z]+$`, false},
+		"/teams/{team:[a-z0-9]+}": {`param "team" constraint changed from ^[a-z]+$ to ^[a-z0-9]+$`, true},
+		"/users/{userID:[0-9]+}":  {`param "id" renamed to "userID", param "userID" constrained to ^[0-9]+$`, true},
+	}
+	if len(diff.Changed) != len(want) {
+		t.Fatalf("expected %d changed routes, got:\n%s", len(want), diff)
+	}
+	for _, c := range diff.Changed {
+		w, ok := want[c.New.Pattern]
+		if !ok {
+			t.Fatalf("unexpected changed route %s", c.New.Pattern)
+		}
+		if changes := strings.Join(c.Changes, ", "); changes != w.changes || c.Breaking != w.breaking {
+			t.Fatalf("%s: expected changes %q (breaking: %v), got %q (breaking: %v)", c.New.Pattern, w.changes, w.breaking, changes, c.Breaking)
+		}
+	}
+
+	if s := diff.String(); !strings.Contains(s, "- DELETE /admin/cache (breaking)\n") || !strings.Contains(s, "+ POST /users\n") {
+		t.Fatalf("unexpected diff report:\n%s", s)
+	}
+}
+
+func TestDiffRoutesCompatible(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	build := func(extra bool) Router {
+		r := NewRouter()
+		r.Get("/{id:[0-9]+}", handler)
+		r.Get("/{slug:[a-z]+}/*", handler)
+		if extra {
+			r.Put("/{id:[0-9]+}", handler)
+		}
+		return r
+	}
+
+	if diff := DiffRoutes(build(false), build(false)); !diff.Empty() || diff.Breaking() {
+		t.Fatalf("expected no differences, got:\n%s", diff)
+	}
+	if diff := DiffRoutes(build(false), build(true)); diff.Empty() || diff.Breaking() {
+		t.Fatalf("expected a compatible difference, got:\n%s", diff)
+	}
+	if diff := DiffRoutes(build(true), build(false)); !diff.Breaking() {
+		t.Fatalf("expected a breaking difference, got:\n%s", diff)
+	}
+}
+
+func TestDiffRoutesSameShape(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	old := NewRouter()
+	old.Get("/u/{id:[0-9]+}", handler)
+	old.Get("/u/{name}", handler)
+	new := NewRouter()
+	new.Get("/u/{uid:[0-9]+}", handler)
+	new.Get("/u/{name}", handler)
+
+	diff := DiffRoutes(old, new)
+	if diff.Breaking() || len(diff.Removed) != 0 || len(diff.Added) != 0 || len(diff.Changed) != 1 {
+		t.Fatalf("expected a non-breaking param rename, got:\n%s", diff)
+	}
+	if got := diff.Changed[0].New.Pattern; got != "/u/{uid:[0-9]+}" {
+		t.Fatalf("expected the rename of /u/{id:[0-9]+}, got %s", got)
+	}
+}
+
+func TestPatShape(t *testing.T) {
+	tests := map[string]string{
+		"/":                        "/",
+		"/users/{id}":              "/users/{}",
+		"/users/{id:[0-9]+}/posts": "/users/{}/posts",
+		"/{a}-{b:[a-z]{2}}.json":   "/{}-{}.json",
+		"/files/{dir}/*":           "/files/{}/*",
+		"/static/*":                "/static/*",
+	}
+	for pattern, want := range tests {
+		if got := patShape(pattern); got != want {
+			t.Errorf("patShape(%q) = %q, expected %q", pattern, got, want)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package chi
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+)
+
+// HandlerFuncE is an http.HandlerFunc that returns an error. Any error is
+// passed on to the ErrorHandler of the router serving the request, so it
+// can be used with every Router method, for example:
+//
+//	r.Get("/users/{id}", chi.HandlerFuncE(getUser).ServeHTTP)
+//	r.Method("POST", "/users", chi.HandlerFuncE(createUser))
+type HandlerFuncE func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP calls fn(w, r), and responds with the router's ErrorHandler if
+// it returns an error.
+//
+// If the handler already wrote the response headers before returning the
+// error, the error handler is still called so the error can be recorded,
+// but anything it writes to the response is discarded.
+func (fn HandlerFuncE) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ww, ew := newErrorWriter(w, r.ProtoMajor)
+	err := fn(ww, r)
+	if err == nil {
+		return
+	}
+
+	eh := errorHandler(r)
+	if ew.wroteHeader {
+		eh(&discardWriter{header: http.Header{}}, r, err)
+		return
+	}
+	eh(w, r, err)
+}
+
+// ErrorHandlerFunc responds to an error returned by a HandlerFuncE.
+type ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+
+// StatusError is an error carrying the HTTP status code to respond with.
+// It is understood by DefaultErrorHandler, and may wrap an underlying error.
+type StatusError struct {
+	Code int
+	Err  error
+}
+
+func (e *StatusError) Error() string {
+	if e.Err == nil {
+		return http.StatusText(e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// DefaultErrorHandler responds with the status code of a StatusError in the
+// error chain, 413 for a request body exceeding http.MaxBytesReader, or 500
+// otherwise. The response body is the status text only, so error details are
+// never leaked to the client.
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	code := http.StatusInternalServerError
+
+	var statusErr *StatusError
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &statusErr) && statusErr.Code >= 100 && statusErr.Code <= 999 {
+		code = statusErr.Code
+	} else if errors.As(err, &maxBytesErr) {
+		code = http.StatusRequestEntityTooLarge
+	}
+
+	http.Error(w, http.StatusText(code), code)
+}
+
+// errorHandler returns the error handler for the request, which is the one
+// of the router that registered the matched route.
+func errorHandler(r *http.Request) ErrorHandlerFunc {
+	rctx := RouteContext(r.Context())
+	if rctx == nil {
+		return DefaultErrorHandler
+	}
+	if rctx.endpoint != nil && rctx.endpoint.mux != nil {
+		if eh := rctx.endpoint.mux.errorHandlerFunc(); eh != nil {
+			return eh
+		}
+	}
+	if mx, ok := rctx.Routes.(*Mux); ok && mx.errorHandler != nil {
+		return mx.errorHandler
+	}
+	return DefaultErrorHandler
+}
+
+// errorWriter is a http.ResponseWriter that records whether the response
+// headers have been written.
+type errorWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+// newErrorWriter wraps `w` into an errorWriter, returned along with a
+// http.ResponseWriter implementing the same optional interfaces as `w`, as
+// middleware.NewWrapResponseWriter does.
+func newErrorWriter(w http.ResponseWriter, protoMajor int) (http.ResponseWriter, *errorWriter) {
+	ew := &errorWriter{ResponseWriter: w}
+	_, fl := w.(http.Flusher)
+
+	if protoMajor == 2 {
+		_, ps := w.(http.Pusher)
+		if fl && ps {
+			return &http2FancyErrorWriter{ew}, ew
+		}
+	} else {
+		_, hj := w.(http.Hijacker)
+		_, rf := w.(io.ReaderFrom)
+		if fl && hj && rf {
+			return &httpFancyErrorWriter{ew}, ew
+		}
+		if fl && hj {
+			return &flushHijackErrorWriter{ew}, ew
+		}
+		if hj {
+			return &hijackErrorWriter{ew}, ew
+		}
+	}
+
+	if fl {
+		return &flushErrorWriter{ew}, ew
+	}
+	return ew, ew
+}
+
+func (w *errorWriter) WriteHeader(code int) {
+	// Informational responses don't commit the final response
+	if code >= 200 {
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *errorWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *errorWriter) flush() {
+	w.wroteHeader = true
+	w.ResponseWriter.(http.Flusher).Flush()
+}
+
+func (w *errorWriter) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	// The connection is the handler's from now on.
+	w.wroteHeader = true
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+// Unwrap returns the original http.ResponseWriter, for use with
+// http.ResponseController.
+func (w *errorWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// flushErrorWriter is an errorWriter implementing http.Flusher.
+type flushErrorWriter struct {
+	*errorWriter
+}
+
+func (w *flushErrorWriter) Flush() { w.flush() }
+
+// hijackErrorWriter is an errorWriter implementing http.Hijacker.
+type hijackErrorWriter struct {
+	*errorWriter
+}
+
+func (w *hijackErrorWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) { return w.hijack() }
+
+// flushHijackErrorWriter is an errorWriter implementing http.Flusher and
+// http.Hijacker.
+type flushHijackErrorWriter struct {
+	*errorWriter
+}
+
+func (w *flushHijackErrorWriter) Flush() { w.flush() }
+
+func (w *flushHijackErrorWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) { return w.hijack() }
+
+// httpFancyErrorWriter is an errorWriter implementing http.Flusher,
+// http.Hijacker and io.ReaderFrom.
+type httpFancyErrorWriter struct {
+	*errorWriter
+}
+
+func (w *httpFancyErrorWriter) Flush() { w.flush() }
+
+func (w *httpFancyErrorWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) { return w.hijack() }
+
+func (w *httpFancyErrorWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.(io.ReaderFrom).ReadFrom(r)
+}
+
+// http2FancyErrorWriter is an errorWriter implementing http.Flusher and
+// http.Pusher.
+type http2FancyErrorWriter struct {
+	*errorWriter
+}
+
+func (w *http2FancyErrorWriter) Flush() { w.flush() }
+
+func (w *http2FancyErrorWriter) Push(target string, opts *http.PushOptions) error {
+	return w.ResponseWriter.(http.Pusher).Push(target, opts)
+}
+
+// discardWriter is a http.ResponseWriter that discards everything written
+// to it.
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(int)             {}
@@ -0,0 +1,198 @@
+package chi
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandlerFuncE(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	r := NewRouter()
+	r.Get("/ok", HandlerFuncE(func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte("ok"))
+		return nil
+	}).ServeHTTP)
+	r.Method("GET", "/missing", HandlerFuncE(func(w http.ResponseWriter, r *http.Request) error {
+		return &StatusError{Code: http.StatusNotFound, Err: errors.New("no such user")}
+	}))
+	r.Get("/wrapped", HandlerFuncE(func(w http.ResponseWriter, r *http.Request) error {
+		return fmt.Errorf("loading: %w", &StatusError{Code: http.StatusConflict})
+	}).ServeHTTP)
+	r.Get("/boom", HandlerFuncE(func(w http.ResponseWriter, r *http.Request) error {
+		return errBoom
+	}).ServeHTTP)
+	r.Post("/upload", HandlerFuncE(func(w http.ResponseWriter, r *http.Request) error {
+		r.Body = http.MaxBytesReader(w, r.Body, 4)
+		_, err := r.Body.Read(make([]byte, 16))
+		return err
+	}).ServeHTTP)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	tests := []struct {
+		method, path string
+		status       int
+		body         string
+	}{
+		{"GET", "/ok", 200, "ok"},
+		{"GET", "/missing", 404, "Not Found\n"},
+		{"GET", "/wrapped", 409, "Conflict\n"},
+		{"GET", "/boom", 500, "Internal Server Error\n"},
+		{"POST", "/upload", 413, "Request Entity Too Large\n"},
+	}
+	for _, tt := range tests {
+		body := strings.NewReader("")
+		if tt.method == "POST" {
+			body = strings.NewReader("too large for the limit")
+		}
+		resp, got := testRequest(t, ts, tt.method, tt.path, body)
+		if resp.StatusCode != tt.status || got != tt.body {
+			t.Fatalf("%s %s: expected %d %q, got %d %q", tt.method, tt.path, tt.status, tt.body, resp.StatusCode, got)
+		}
+	}
+}
+
+func TestMuxErrorHandler(t *testing.T) {
+	jsonError := func(prefix string) ErrorHandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, err error) {
+			code := 500
+			var se *StatusError
+			if errors.As(err, &se) {
+				code = se.Code
+			}
+			w.WriteHeader(code)
+			w.Write([]byte(prefix + ": " + err.Error()))
+		}
+	}
+	fail := func(code int) http.HandlerFunc {
+		return HandlerFuncE(func(w http.ResponseWriter, r *http.Request) error {
+			return &StatusError{Code: code, Err: errors.New("failed")}
+		}).ServeHTTP
+	}
+
+	r := NewRouter()
+	r.Get("/root", fail(400))
+	r.Group(func(r Router) {
+		r.(*Mux).ErrorHandler(jsonError("group"))
+		r.Get("/group", fail(422))
+	})
+	r.Route("/inherit", func(r Router) {
+		r.Get("/", fail(403))
+	})
+	r.Route("/own", func(r Router) {
+		r.(*Mux).ErrorHandler(jsonError("own"))
+		r.Get("/", fail(404))
+	})
+	r.ErrorHandler(jsonError("root"))
+
+	sr := NewRouter()
+	sr.Get("/", fail(409))
+	r.Mount("/mounted", sr)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/root", 400, "root: failed"},
+		{"/group", 422, "group: failed"},
+		{"/inherit", 403, "root: failed"},
+		{"/own", 404, "own: failed"},
+		{"/mounted", 409, "root: failed"},
+	}
+	for _, tt := range tests {
+		resp, body := testRequest(t, ts, "GET", tt.path, nil)
+		if resp.StatusCode != tt.status || body != tt.body {
+			t.Fatalf("%s: expected %d %q, got %d %q", tt.path, tt.status, tt.body, resp.StatusCode, body)
+		}
+	}
+}
+
+func TestHandlerFuncEHeadersWritten(t *testing.T) {
+	var handled error
+
+	r := NewRouter()
+	r.ErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		handled = err
+		w.WriteHeader(500)
+		w.Write([]byte("error page"))
+	})
+	r.Get("/partial", HandlerFuncE(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(200)
+		w.Write([]byte("partial"))
+		return errors.New("stream broken")
+	}).ServeHTTP)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/partial", nil))
+
+	if w.Code != 200 || w.Body.String() != "partial" {
+		t.Fatalf("expected the written response to be kept, got %d %q", w.Code, w.Body.String())
+	}
+	if handled == nil || handled.Error() != "stream broken" {
+		t.Fatalf("expected error handler to be called, got %v", handled)
+	}
+}
+
+func TestHandlerFuncEUpgrade(t *testing.T) {
+	r := NewRouter()
+	r.Get("/ws", HandlerFuncE(func(w http.ResponseWriter, r *http.Request) error {
+		if _, ok := w.(io.ReaderFrom); !ok {
+			return errors.New("expected an io.ReaderFrom")
+		}
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			return errors.New("expected a http.Hijacker")
+		}
+		conn, rw, err := hj.Hijack()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: echo\r\nConnection: Upgrade\r\n\r\n")
+		rw.Flush()
+		line, _ := rw.ReadString('\n')
+		rw.WriteString("echo " + line)
+		rw.Flush()
+
+		// The error handler must not write to the hijacked connection.
+		return errors.New("closed")
+	}).ServeHTTP)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	fmt.Fprint(conn, "GET /ws HTTP/1.1\r\nHost: x\r\nUpgrade: echo\r\nConnection: Upgrade\r\n\r\n")
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected status 101, got %d", resp.StatusCode)
+	}
+
+	fmt.Fprint(conn, "hello\n")
+	if line, _ := br.ReadString('\n'); line != "echo hello\n" {
+		t.Fatalf("unexpected echo %q", line)
+	}
+}
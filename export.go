@@ -0,0 +1,178 @@
+package chi
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// ExportFormat is an output format of ExportRoutes.
+type ExportFormat int
+
+const (
+	// ExportJSON formats the routes as an indented JSON array of RouteInfo.
+	ExportJSON ExportFormat = iota
+
+	// ExportMarkdown formats the routes as a Markdown table.
+	ExportMarkdown
+)
+
+// RouteInfo describes a route registered on a router, as reported by
+// ExportRoutes and DiffRoutes.
+type RouteInfo struct {
+	// Method is the HTTP method of the route.
+	Method string `json:"method"`
+
+	// Pattern is the full routing pattern, including the patterns the
+	// sub-routers were mounted on.
+	Pattern string `json:"pattern"`
+
+	// Params are the URL params of the pattern, in order.
+	Params []RouteParam `json:"params,omitempty"`
+
+	// Mount is the pattern of the sub-router the route was registered on,
+	// or "/" for the root router.
+	Mount string `json:"mount"`
+
+	// Middlewares are the names of the middlewares wrapping the handler,
+	// in order. See MiddlewareName.
+	Middlewares []string `json:"middlewares,omitempty"`
+
+	// Handler is the name of the handler function or type.
+	Handler string `json:"handler"`
+}
+
+// RouteParam is a URL param of a routing pattern.
+type RouteParam struct {
+	// Name is the key of the param, as used with URLParam.
+	Name string `json:"name"`
+
+	// Regexp is the anchored regexp constraining the param, if any.
+	Regexp string `json:"regexp,omitempty"`
+}
+
+// ExportRoutes returns the routes of `r`, including the ones of its mounted
+// sub-routers, formatted as a table sorted by pattern and method.
+//
+// The output only depends on the routes, so it can be checked into a
+// repository and compared in code review to catch accidental API changes.
+// Name the middlewares with Named to make them stand out in the table.
+func ExportRoutes(r Routes, format ExportFormat) ([]byte, error) {
+	routes := routeInfos(r)
+
+	switch format {
+	case ExportJSON:
+		b, err := json.MarshalIndent(routes, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	case ExportMarkdown:
+		return markdownRoutes(routes), nil
+	}
+	return nil, fmt.Errorf("chi: unknown route export format %d", format)
+}
+
+// routeInfos returns the routes of `r`, sorted by pattern and method.
+func routeInfos(r Routes) []RouteInfo {
+	routes := []RouteInfo{}
+	walk(r, func(mount, method, route string, handler http.Handler, middlewares []func(http.Handler) http.Handler) error {
+		if mount == "" {
+			mount = "/"
+		}
+		routes = append(routes, RouteInfo{
+			Method:      method,
+			Pattern:     route,
+			Params:      patParams(route),
+			Mount:       mount,
+			Middlewares: Middlewares(middlewares).Names(),
+			Handler:     handlerName(handler),
+		})
+		return nil
+	}, "", nil, nil)
+
+	slices.SortFunc(routes, func(a, b RouteInfo) int {
+		return cmp.Or(strings.Compare(a.Pattern, b.Pattern), strings.Compare(a.Method, b.Method))
+	})
+	return routes
+}
+
+// markdownRoutes formats the routes as a Markdown table.
+func markdownRoutes(routes []RouteInfo) []byte {
+	var b bytes.Buffer
+	b.WriteString("| Method | Pattern | Params | Mount | Middlewares | Handler |\n")
+	b.WriteString("|--------|---------|--------|-------|-------------|---------|\n")
+
+	for _, rt := range routes {
+		params := make([]string, len(rt.Params))
+		for i, p := range rt.Params {
+			params[i] = markdownCode(p.Name)
+			if p.Regexp != "" {
+				params[i] += " " + markdownCode(p.Regexp)
+			}
+		}
+		middlewares := make([]string, len(rt.Middlewares))
+		for i, name := range rt.Middlewares {
+			middlewares[i] = markdownCode(name)
+		}
+
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+			rt.Method,
+			markdownCode(rt.Pattern),
+			strings.Join(params, ", "),
+			markdownCode(rt.Mount),
+			strings.Join(middlewares, ", "),
+			markdownCode(rt.Handler),
+		)
+	}
+	return b.Bytes()
+}
+
+// markdownCode returns s as a code span of a Markdown table cell.
+func markdownCode(s string) string {
+	return "`" + strings.ReplaceAll(s, "|", `\|`) + "`"
+}
+
+// patParams returns the URL params of a routing pattern, in order.
+func patParams(pattern string) []RouteParam {
+	var params []RouteParam
+	for pat := pattern; ; {
+		ptyp, key, rexpat, _, _, e := patNextSegment(pat)
+		if ptyp == ntStatic {
+			return params
+		}
+		params = append(params, RouteParam{Name: key, Regexp: rexpat})
+		pat = pat[e:]
+	}
+}
+
+// handlerName returns the name of the function or type of a handler.
+func handlerName(h http.Handler) string {
+	switch h := h.(type) {
+	case *metadataHandler:
+		return handlerName(h.handler)
+	case *aliasHandler:
+		return handlerName(h.handler)
+	case interface{ endpointFunc() any }:
+		return funcName(h.endpointFunc())
+	}
+
+	if v := reflect.ValueOf(h); v.Kind() == reflect.Func {
+		return funcName(h)
+	}
+	return fmt.Sprintf("%T", h)
+}
+
+// funcName returns the name of the function fn, as reported by the runtime.
+func funcName(fn any) string {
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return ""
+}
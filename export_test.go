@@ -0,0 +1,123 @@
+package chi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func exportTestHandler(w http.ResponseWriter, r *http.Request) {}
+
+func exportTestRouter() *Mux {
+	mw := func(next http.Handler) http.Handler { return next }
+	getUser := func(ctx context.Context, in struct{}) (struct{}, error) { return struct{}{}, nil }
+
+	r := NewRouter()
+	r.Use(Named("requestID", mw))
+	r.Get("/", exportTestHandler)
+
+	r.Route("/api", func(r Router) {
+		r.Use(Named("auth", mw))
+		r.With(Named("ratelimit", mw)).Get("/users/{id:[0-9]+}", exportTestHandler)
+		r.Method("GET", "/users/{id}/profile", WithMetadata(JSON(getUser), Metadata{"summary": "profile"}))
+		r.Delete("/users/{id}", exportTestHandler)
+	})
+	r.Get("/files/{name:a|b}", exportTestHandler)
+	return r
+}
+
+func TestExportRoutesJSON(t *testing.T) {
+	b, err := ExportRoutes(exportTestRouter(), ExportJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var routes []RouteInfo
+	if err := json.Unmarshal(b, &routes); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []RouteInfo{
+		{
+			Method:      "GET",
+			Pattern:     "/",
+			Mount:       "/",
+			Middlewares: []string{"requestID"},
+			Handler:     "github.com/go-chi/chi/v5.exportTestHandler",
+		},
+		{
+			Method:      "GET",
+			Pattern:     "/api/users/{id:[0-9]+}",
+			Params:      []RouteParam{{Name: "id", Regexp: "^[0-9]+$"}},
+			Mount:       "/api",
+			Middlewares: []string{"requestID", "auth", "ratelimit"},
+			Handler:     "github.com/go-chi/chi/v5.exportTestHandler",
+		},
+		{
+			Method:      "DELETE",
+			Pattern:     "/api/users/{id}",
+			Params:      []RouteParam{{Name: "id"}},
+			Mount:       "/api",
+			Middlewares: []string{"requestID", "auth"},
+			Handler:     "github.com/go-chi/chi/v5.exportTestHandler",
+		},
+		{
+			Method:      "GET",
+			Pattern:     "/api/users/{id}/profile",
+			Params:      []RouteParam{{Name: "id"}},
+			Mount:       "/api",
+			Middlewares: []string{"requestID", "auth"},
+			Handler:     "github.com/go-chi/chi/v5.exportTestRouter.func2",
+		},
+		{
+			Method:      "GET",
+			Pattern:     "/files/{name:a|b}",
+			Params:      []RouteParam{{Name: "name", Regexp: "^a|b$"}},
+			Mount:       "/",
+			Middlewares: []string{"requestID"},
+			Handler:     "github.com/go-chi/chi/v5.exportTestHandler",
+		},
+	}
+	if !reflect.DeepEqual(routes, want) {
+		t.Fatalf("unexpected routes:\n%s", b)
+	}
+
+	// The export must be stable across router constructions.
+	again, err := ExportRoutes(exportTestRouter(), ExportJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(b) {
+		t.Fatalf("expected a stable export, got:\n%s\nthen:\n%s", b, again)
+	}
+}
+
+func TestExportRoutesMarkdown(t *testing.T) {
+	b, err := ExportRoutes(exportTestRouter(), ExportMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("expected a header, a delimiter and 5 routes, got:\n%s", b)
+	}
+	if lines[0] != "| Method | Pattern | Params | Mount | Middlewares | Handler |" {
+		t.Fatalf("unexpected header: %s", lines[0])
+	}
+	if want := "| GET | `/api/users/{id:[0-9]+}` | `id` `^[0-9]+$` | `/api` | `requestID`, `auth`, `ratelimit` | `github.com/go-chi/chi/v5.exportTestHandler` |"; lines[3] != want {
+		t.Fatalf("unexpected route line:\n%s\nexpected:\n%s", lines[3], want)
+	}
+	if want := "| GET | `/files/{name:a\\|b}` | `name` `^a\\|b$` | `/` | `requestID` | `github.com/go-chi/chi/v5.exportTestHandler` |"; lines[6] != want {
+		t.Fatalf("expected pipes to be escaped:\n%s\nexpected:\n%s", lines[6], want)
+	}
+}
+
+func TestExportRoutesUnknownFormat(t *testing.T) {
+	if _, err := ExportRoutes(NewRouter(), ExportFormat(42)); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
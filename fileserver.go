@@ -0,0 +1,302 @@
+package chi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileServerOptions configures the behaviour of FileServer. The zero value
+// serves plain files with directory listings disabled.
+type FileServerOptions struct {
+	// Index is the file served for directory requests and used as the SPA
+	// fallback document. Defaults to "index.html".
+	Index string
+
+	// SPA enables single-page-application mode: requests for paths that
+	// don't exist and carry no file extension are answered with the root
+	// Index file instead of a 404, so client-side routes can be deep linked.
+	SPA bool
+
+	// DirectoryListing enables listings for directories without an Index
+	// file. Listings are disabled by default.
+	DirectoryListing bool
+
+	// Precompressed serves a "<name>.gz" sibling, if present, to clients
+	// that accept gzip encoding.
+	Precompressed bool
+
+	// Immutable reports whether the file at name has a content hash in its
+	// name, and may therefore be cached forever by clients. Defaults to
+	// matching names such as "app.3f2a9c1b.js" or "main-4f3c2a1b9e.css".
+	Immutable func(name string) bool
+}
+
+// FileServer mounts a static file server along the `path` of the router,
+// serving the contents of `fsys`, which may be an embed.FS, os.DirFS or any
+// other fs.FS. The files are registered as GET and HEAD routes along
+// ./path/*, so the mount shows up in Routes() and Walk() like any other route.
+//
+// Responses carry a strong ETag computed from the file contents, and a
+// Last-Modified header whenever the filesystem reports a modification time,
+// so conditional and range requests are handled by http.ServeContent. A nil
+// `opts` uses the defaults described on FileServerOptions.
+func FileServer(r Router, path string, fsys fs.FS, opts *FileServerOptions) {
+	if strings.ContainsAny(path, "{}*") {
+		panic("chi: FileServer does not permit any URL parameters.")
+	}
+	if fsys == nil {
+		panic("chi: attempting to serve files from a nil fs.FS")
+	}
+
+	fsrv := newFileServer(fsys, opts)
+
+	if path != "/" && path[len(path)-1] != '/' {
+		r.Get(path, redirectTrailingSlash)
+		path += "/"
+	}
+	path += "*"
+
+	notFound := http.NotFound
+	if mx, ok := r.(interface{ NotFoundHandler() http.HandlerFunc }); ok {
+		notFound = func(w http.ResponseWriter, r *http.Request) {
+			mx.NotFoundHandler().ServeHTTP(w, r)
+		}
+	}
+	fsrv.notFound = notFound
+
+	r.Get(path, fsrv.ServeHTTP)
+	r.Head(path, fsrv.ServeHTTP)
+}
+
+// defaultImmutable matches file names carrying a hex content hash of at
+// least 8 characters before their extension, as emitted by most bundlers.
+var defaultImmutable = regexp.MustCompile(`[.-][0-9a-f]{8,}\.[^./]+$`)
+
+type fileServer struct {
+	fsys     fs.FS
+	opts     FileServerOptions
+	notFound http.HandlerFunc
+
+	// etags caches the computed ETag of each served file, keyed by name.
+	etags sync.Map
+}
+
+type fileETag struct {
+	modTime time.Time
+	size    int64
+	etag    string
+}
+
+func newFileServer(fsys fs.FS, opts *FileServerOptions) *fileServer {
+	fsrv := &fileServer{fsys: fsys, notFound: http.NotFound}
+	if opts != nil {
+		fsrv.opts = *opts
+	}
+	if fsrv.opts.Index == "" {
+		fsrv.opts.Index = "index.html"
+	}
+	if fsrv.opts.Immutable == nil {
+		fsrv.opts.Immutable = defaultImmutable.MatchString
+	}
+	return fsrv
+}
+
+func (fsrv *fileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The wildcard param holds the escaped path when the request URL has a
+	// RawPath, see Mux#routeHTTP.
+	name := URLParam(r, "*")
+	if r.URL.RawPath != "" {
+		var err error
+		if name, err = url.PathUnescape(name); err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+	}
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		name = "."
+	}
+	if !fs.ValidPath(name) {
+		fsrv.notFound(w, r)
+		return
+	}
+
+	info, err := fs.Stat(fsrv.fsys, name)
+	if err != nil {
+		fsrv.fallback(w, r, name)
+		return
+	}
+
+	if !info.IsDir() {
+		fsrv.serveFile(w, r, name, info)
+		return
+	}
+
+	// Directories are always addressed with a trailing slash, so relative
+	// links inside an index file resolve correctly.
+	if !strings.HasSuffix(r.URL.Path, "/") {
+		redirectTrailingSlash(w, r)
+		return
+	}
+
+	index := path.Join(name, fsrv.opts.Index)
+	if info, err := fs.Stat(fsrv.fsys, index); err == nil && !info.IsDir() {
+		fsrv.serveFile(w, r, index, info)
+		return
+	}
+
+	if fsrv.opts.DirectoryListing {
+		http.ServeFileFS(w, r, fsrv.fsys, name)
+		return
+	}
+	fsrv.fallback(w, r, name)
+}
+
+// fallback responds to a request for a file that doesn't exist, either with
+// the SPA index document or a 404.
+func (fsrv *fileServer) fallback(w http.ResponseWriter, r *http.Request, name string) {
+	if !fsrv.opts.SPA || path.Ext(name) != "" {
+		fsrv.notFound(w, r)
+		return
+	}
+
+	info, err := fs.Stat(fsrv.fsys, fsrv.opts.Index)
+	if err != nil || info.IsDir() {
+		fsrv.notFound(w, r)
+		return
+	}
+
+	// The index document changes with every deploy, while the URL that
+	// serves it doesn't, so clients must always revalidate it.
+	w.Header().Set("Cache-Control", "no-cache")
+	fsrv.serveFile(w, r, fsrv.opts.Index, info)
+}
+
+func (fsrv *fileServer) serveFile(w http.ResponseWriter, r *http.Request, name string, info fs.FileInfo) {
+	h := w.Header()
+
+	if fsrv.opts.Immutable(name) {
+		h.Set("Cache-Control", "public, max-age=31536000, immutable")
+	}
+
+	if fsrv.opts.Precompressed {
+		h.Add("Vary", "Accept-Encoding")
+		if acceptsGzip(r) {
+			gzName := name + ".gz"
+			if gzInfo, err := fs.Stat(fsrv.fsys, gzName); err == nil && !gzInfo.IsDir() {
+				ctype := mime.TypeByExtension(path.Ext(name))
+				if ctype == "" {
+					ctype = "application/octet-stream"
+				}
+				h.Set("Content-Type", ctype)
+				h.Set("Content-Encoding", "gzip")
+				fsrv.serveContent(w, r, gzName, gzInfo)
+				return
+			}
+		}
+	}
+
+	fsrv.serveContent(w, r, name, info)
+}
+
+func (fsrv *fileServer) serveContent(w http.ResponseWriter, r *http.Request, name string, info fs.FileInfo) {
+	f, err := fsrv.fsys.Open(name)
+	if err != nil {
+		fsrv.notFound(w, r)
+		return
+	}
+	defer f.Close()
+
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		b, err := io.ReadAll(f)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		content = bytes.NewReader(b)
+	}
+
+	etag, err := fsrv.etag(name, info, content)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("ETag", etag)
+
+	http.ServeContent(w, r, path.Base(name), info.ModTime(), content)
+}
+
+// etag returns the strong ETag for the file contents, computing and caching
+// it the first time a file is served, or whenever the file has changed.
+func (fsrv *fileServer) etag(name string, info fs.FileInfo, content io.ReadSeeker) (string, error) {
+	if v, ok := fsrv.etags.Load(name); ok {
+		e := v.(*fileETag)
+		if e.modTime.Equal(info.ModTime()) && e.size == info.Size() {
+			return e.etag, nil
+		}
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	e := &fileETag{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		etag:    `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`,
+	}
+	fsrv.etags.Store(name, e)
+	return e.etag, nil
+}
+
+// redirectTrailingSlash redirects the request to the same path with a
+// trailing slash. The target is relative, so it also works for a file
+// server on a mounted sub-router.
+func redirectTrailingSlash(w http.ResponseWriter, r *http.Request) {
+	target := path.Base(r.URL.Path) + "/"
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+	http.Redirect(w, r, target, http.StatusMovedPermanently)
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding header allows
+// a gzip encoded response.
+func acceptsGzip(r *http.Request) bool {
+	for _, v := range r.Header.Values("Accept-Encoding") {
+		for _, coding := range strings.Split(v, ",") {
+			coding, params, _ := strings.Cut(strings.TrimSpace(coding), ";")
+			if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+				continue
+			}
+			params = strings.ReplaceAll(params, " ", "")
+			if q, ok := strings.CutPrefix(params, "q="); ok && isZeroQ(q) {
+				return false
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// isZeroQ reports whether a qvalue such as "0" or "0.000" is zero.
+func isZeroQ(q string) bool {
+	q = strings.TrimRight(q, "0")
+	return q == "" || q == "0."
+}
@@ -0,0 +1,214 @@
+package chi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func testFS() fstest.MapFS {
+	modTime := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	return fstest.MapFS{
+		"index.html":            {Data: []byte("<html>app</html>"), ModTime: modTime},
+		"notes.txt":             {Data: []byte("notes"), ModTime: modTime},
+		"100%.txt":              {Data: []byte("percent"), ModTime: modTime},
+		"app.3f2a9c1b.js":       {Data: []byte("console.log(1)"), ModTime: modTime},
+		"style.css":             {Data: []byte("body{}"), ModTime: modTime},
+		"style.css.gz":          {Data: []byte("gzipped-css"), ModTime: modTime},
+		"docs/index.html":       {Data: []byte("<html>docs</html>"), ModTime: modTime},
+		"assets/logo.svg":       {Data: []byte("<svg/>"), ModTime: modTime},
+		"assets/nested/one.txt": {Data: []byte("one"), ModTime: modTime},
+	}
+}
+
+func TestFileServer(t *testing.T) {
+	r := NewRouter()
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("root"))
+	})
+	FileServer(r, "/static", testFS(), nil)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	if _, body := testRequest(t, ts, "GET", "/", nil); body != "root" {
+		t.Fatalf("expected root handler, got %q", body)
+	}
+
+	resp, body := testRequest(t, ts, "GET", "/static/notes.txt", nil)
+	if resp.StatusCode != 200 || body != "notes" {
+		t.Fatalf("expected 200 notes, got %d %q", resp.StatusCode, body)
+	}
+	if resp.Header.Get("Last-Modified") != "Wed, 01 May 2024 00:00:00 GMT" {
+		t.Fatalf("unexpected Last-Modified %q", resp.Header.Get("Last-Modified"))
+	}
+	etag := resp.Header.Get("ETag")
+	if !strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, "W/") {
+		t.Fatalf("expected a strong ETag, got %q", etag)
+	}
+
+	req, _ := http.NewRequest("GET", ts.URL+"/static/notes.txt", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotModified {
+		t.Fatalf("expected 304 for a matching ETag, got %d", resp.StatusCode)
+	}
+
+	if resp, body := testRequest(t, ts, "HEAD", "/static/notes.txt", nil); resp.StatusCode != 200 || body != "" {
+		t.Fatalf("expected 200 with empty body for HEAD, got %d %q", resp.StatusCode, body)
+	}
+	if _, body := testRequest(t, ts, "GET", "/static/", nil); body != "<html>app</html>" {
+		t.Fatalf("expected index, got %q", body)
+	}
+	if _, body := testRequest(t, ts, "GET", "/static/docs/", nil); body != "<html>docs</html>" {
+		t.Fatalf("expected docs index, got %q", body)
+	}
+	if resp, _ := testRequest(t, ts, "GET", "/static/assets/", nil); resp.StatusCode != 404 {
+		t.Fatalf("expected directory listing to be disabled, got %d", resp.StatusCode)
+	}
+	if resp, _ := testRequest(t, ts, "GET", "/static/missing", nil); resp.StatusCode != 404 {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+	if resp, body := testRequest(t, ts, "GET", "/static/100%25.txt", nil); resp.StatusCode != 200 || body != "percent" {
+		t.Fatalf("expected 200 percent, got %d %q", resp.StatusCode, body)
+	}
+	if resp, _ := testRequest(t, ts, "GET", "/static/100%2525.txt", nil); resp.StatusCode != 404 {
+		t.Fatalf("expected 404 for a path unescaped twice, got %d", resp.StatusCode)
+	}
+	if resp, _ := testRequest(t, ts, "GET", "/static/../fileserver.go", nil); resp.StatusCode != 404 {
+		t.Fatalf("expected 404 for path traversal, got %d", resp.StatusCode)
+	}
+
+	resp, _ = testRequest(t, ts, "GET", "/static/app.3f2a9c1b.js", nil)
+	if cc := resp.Header.Get("Cache-Control"); cc != "public, max-age=31536000, immutable" {
+		t.Fatalf("expected immutable caching for hashed file, got %q", cc)
+	}
+	resp, _ = testRequest(t, ts, "GET", "/static/notes.txt", nil)
+	if cc := resp.Header.Get("Cache-Control"); cc != "" {
+		t.Fatalf("expected no caching for regular file, got %q", cc)
+	}
+}
+
+func TestFileServerRedirects(t *testing.T) {
+	r := NewRouter()
+	FileServer(r, "/static", testFS(), nil)
+	r.Route("/web", func(r Router) {
+		FileServer(r, "/static", testFS(), nil)
+	})
+
+	tests := []struct {
+		path     string
+		location string
+	}{
+		{"/static", "/static/"},
+		{"/static?x=1", "/static/?x=1"},
+		{"/web/static", "/web/static/"},
+		{"/static/docs", "/static/docs/"},
+		{"/web/static/docs?x=1", "/web/static/docs/?x=1"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != tt.location {
+			t.Fatalf("%s: expected redirect to %q, got %d %q", tt.path, tt.location, w.Code, w.Header().Get("Location"))
+		}
+	}
+}
+
+func TestFileServerOptions(t *testing.T) {
+	r := NewRouter()
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+		w.Write([]byte("custom 404"))
+	})
+	FileServer(r, "/", testFS(), &FileServerOptions{
+		SPA:              true,
+		DirectoryListing: true,
+		Precompressed:    true,
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	resp, body := testRequest(t, ts, "GET", "/users/123", nil)
+	if resp.StatusCode != 200 || body != "<html>app</html>" {
+		t.Fatalf("expected SPA fallback, got %d %q", resp.StatusCode, body)
+	}
+	if cc := resp.Header.Get("Cache-Control"); cc != "no-cache" {
+		t.Fatalf("expected SPA fallback to be revalidated, got %q", cc)
+	}
+
+	resp, body = testRequest(t, ts, "GET", "/missing.js", nil)
+	if resp.StatusCode != 404 || body != "custom 404" {
+		t.Fatalf("expected router 404 for missing asset, got %d %q", resp.StatusCode, body)
+	}
+
+	if _, body := testRequest(t, ts, "GET", "/assets/", nil); !strings.Contains(body, `<a href="logo.svg">logo.svg</a>`) {
+		t.Fatalf("expected directory listing, got %q", body)
+	}
+
+	req, _ := http.NewRequest("GET", ts.URL+"/style.css", nil)
+	req.Header.Set("Accept-Encoding", "br, gzip")
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.Header.Get("Content-Encoding") != "gzip" || resp.ContentLength != int64(len("gzipped-css")) {
+		t.Fatalf("expected precompressed sibling, got %q %d", resp.Header.Get("Content-Encoding"), resp.ContentLength)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/css") {
+		t.Fatalf("expected original content type, got %q", resp.Header.Get("Content-Type"))
+	}
+	if resp.Header.Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("expected Vary header, got %q", resp.Header.Get("Vary"))
+	}
+
+	req.Header.Set("Accept-Encoding", "gzip;q=0")
+	resp, err = http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.Header.Get("Content-Encoding") != "" {
+		t.Fatalf("expected identity encoding, got %q", resp.Header.Get("Content-Encoding"))
+	}
+}
+
+func TestFileServerRoutes(t *testing.T) {
+	r := NewRouter()
+	r.Route("/web", func(r Router) {
+		FileServer(r, "/assets", testFS(), nil)
+	})
+
+	var routes []string
+	Walk(r, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		routes = append(routes, method+" "+route)
+		return nil
+	})
+
+	for _, want := range []string{"GET /web/assets", "GET /web/assets/*", "HEAD /web/assets/*"} {
+		found := false
+		for _, rt := range routes {
+			found = found || rt == want
+		}
+		if !found {
+			t.Fatalf("expected %q in routes %v", want, routes)
+		}
+	}
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	if _, body := testRequest(t, ts, "GET", "/web/assets/assets/nested/one.txt", nil); body != "one" {
+		t.Fatalf("expected file from mounted server, got %q", body)
+	}
+}
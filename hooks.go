@@ -0,0 +1,143 @@
+package chi
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Hooks are callbacks observing the lifecycle of a router: the registration
+// of its routes and the routing decision made for every request. See
+// Mux#Hooks.
+type Hooks struct {
+	// OnRegister is called for every route registered on the router, once
+	// per method, as reported by Walk. A sub-router mounted on the router
+	// reports the routes registered on it before the mount as well.
+	OnRegister func(route RegisteredRoute)
+
+	// OnMatch is called when a request matched a route, right before the
+	// route handler serves it. The routing context of the request describes
+	// the route, see Context#Route.
+	OnMatch func(r *http.Request)
+
+	// OnNotFound is called when no route matches the request path, before
+	// the NotFound handler responds.
+	OnNotFound func(r *http.Request)
+
+	// OnMethodNotAllowed is called when routes match the request path, but
+	// none for the request method, before the MethodNotAllowed handler
+	// responds.
+	OnMethodNotAllowed func(r *http.Request)
+}
+
+// RegisteredRoute is a route reported to Hooks.OnRegister.
+type RegisteredRoute struct {
+	// Method is the HTTP method of the route.
+	Method string
+
+	// Pattern is the full routing pattern of the route, including the
+	// patterns of the parent routers the hooks are inherited from.
+	Pattern string
+
+	// Handler is the route handler, without its inline middlewares.
+	Handler http.Handler
+
+	// Middlewares are the middlewares wrapping the route handler, in the
+	// same order as reported by Walk.
+	Middlewares Middlewares
+}
+
+// Hooks sets the lifecycle hooks of the router. The hooks are inherited by
+// the sub-routers mounted with Mount and Route, unless they set hooks of
+// their own.
+func (mx *Mux) Hooks(hooks Hooks) {
+	tm := mx.treeMux()
+	tm.hooks, tm.hooksInherited = &hooks, false
+	tm.hooksPrefix, tm.hooksMiddlewares, tm.hooksMatched = "", nil, nil
+	tm.updateSubHooks()
+}
+
+// mountHooks hands the hooks of the router down to the sub-router mounted
+// on `pattern` through `mx`, and reports the routes registered on the
+// sub-router so far to OnRegister.
+func (mx *Mux) mountHooks(subr *Mux, pattern string) {
+	if !mx.inheritHooks(subr, pattern) || subr.hooks.OnRegister == nil {
+		return
+	}
+	walk(subr, func(_, method, route string, handler http.Handler, middlewares []func(http.Handler) http.Handler) error {
+		subr.hooks.OnRegister(RegisteredRoute{
+			Method: method, Pattern: route, Handler: handler, Middlewares: middlewares,
+		})
+		return nil
+	}, subr.hooksPrefix, subr.hooksMiddlewares, subr.hooksMatched)
+}
+
+// inheritHooks hands the hooks of the router down to the sub-router mounted
+// on `pattern` through `mx`, unless the sub-router has hooks of its own or
+// already inherited them. It reports whether the hooks were handed down.
+func (mx *Mux) inheritHooks(subr *Mux, pattern string) bool {
+	tm := mx.treeMux()
+	if tm.hooks == nil || subr.hooks == tm.hooks || (subr.hooks != nil && !subr.hooksInherited) {
+		return false
+	}
+
+	mws := slices.Concat(tm.hooksMiddlewares, tm.middlewares)
+	if mx.inline {
+		mws = append(mws, mx.middlewares...)
+	}
+	subr.hooks, subr.hooksInherited = tm.hooks, true
+	subr.hooksPrefix = tm.hooksPrefix + strings.TrimSuffix(pattern, "/")
+	subr.hooksMiddlewares = mws
+	subr.hooksMatched = slices.Concat(tm.hooksMatched, tm.matchedMiddlewares)
+	subr.updateSubHooks()
+	return true
+}
+
+// updateSubHooks hands the hooks of the router down to the sub-routers
+// already mounted on it.
+func (mx *Mux) updateSubHooks() {
+	for _, route := range mx.tree.routes(mx.methodName) {
+		subMux, ok := mountedMux(route.SubRoutes)
+		if !ok {
+			continue
+		}
+		im := mx
+		if chain, ok := route.Handlers["*"].(*ChainHandler); ok {
+			im = &Mux{parent: mx, tree: mx.tree, inline: true, middlewares: chain.Middlewares}
+		}
+		if subMux.hooksInherited {
+			subMux.hooks = nil
+		}
+		im.inheritHooks(subMux, strings.TrimSuffix(route.Pattern, "*"))
+	}
+}
+
+// registerHooks reports a route registered on the mux to the OnRegister
+// hook of the router.
+func (mx *Mux) registerHooks(method methodTyp, pattern string, handler http.Handler) {
+	tm := mx.treeMux()
+	if tm.hooks == nil || tm.hooks.OnRegister == nil || method&mSTUB == mSTUB {
+		return
+	}
+
+	mws := slices.Concat(tm.hooksMiddlewares, tm.middlewares, tm.hooksMatched, tm.matchedMiddlewares)
+	if mx.inline {
+		mws = append(mws, mx.middlewares...)
+	}
+
+	var methods []string
+	if method&mALL == mALL {
+		for m := range methodMap {
+			methods = append(methods, m)
+		}
+		slices.Sort(methods)
+	} else {
+		methods = append(methods, tm.methodName(method))
+	}
+
+	for _, m := range methods {
+		tm.hooks.OnRegister(RegisteredRoute{
+			Method: m, Pattern: tm.hooksPrefix + pattern, Handler: handler, Middlewares: mws,
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package chi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	var registered, events []string
+
+	mw := func(next http.Handler) http.Handler { return next }
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+
+	// A sub-router built before being mounted reports its routes on mount.
+	admin := NewRouter()
+	admin.Use(Named("adminOnly", mw))
+	admin.Get("/stats", ok)
+
+	r := NewRouter()
+	r.Use(Named("logger", mw))
+	r.Hooks(Hooks{
+		OnRegister: func(route RegisteredRoute) {
+			registered = append(registered, route.Method+" "+route.Pattern+" "+route.Middlewares.Names()[len(route.Middlewares)-1])
+		},
+		OnMatch: func(r *http.Request) {
+			events = append(events, "match "+RouteContext(r.Context()).RoutePattern())
+		},
+		OnNotFound: func(r *http.Request) {
+			events = append(events, "404 "+r.URL.Path)
+		},
+		OnMethodNotAllowed: func(r *http.Request) {
+			events = append(events, "405 "+r.URL.Path+" "+strings.Join(RouteContext(r.Context()).Route().AllowedMethods, ","))
+		},
+	})
+	r.Get("/", ok)
+	r.Route("/users", func(r Router) {
+		r.With(Named("auth", mw)).Post("/{id}", ok)
+	})
+	r.With(Named("admin", mw)).Mount("/admin", admin)
+	r.Mount("/static", http.NotFoundHandler())
+
+	want := []string{
+		"GET / logger",
+		"POST /users/{id} auth",
+		"GET /admin/stats adminOnly",
+	}
+	for _, m := range []string{"CONNECT", "DELETE", "GET", "HEAD", "OPTIONS", "PATCH", "POST", "PUT", "QUERY", "TRACE"} {
+		want = append(want, m+" /static/* logger")
+	}
+	if !slices.Equal(registered, want) {
+		t.Fatalf("unexpected registered routes:\n%q\nexpected:\n%q", registered, want)
+	}
+
+	for _, req := range [][2]string{
+		{"GET", "/"},
+		{"POST", "/users/1"},
+		{"GET", "/users/1"},
+		{"GET", "/admin/stats"},
+		{"GET", "/admin/nope"},
+		{"GET", "/static/a.css"},
+		{"GET", "/static"},
+		{"FOO", "/"},
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(req[0], req[1], nil))
+	}
+
+	want = []string{
+		"match /",
+		"match /users/{id}",
+		"405 /users/1 POST",
+		"match /admin/stats",
+		"404 /admin/nope",
+		"match /static/*",
+		"match /static",
+		"405 / ",
+	}
+	if !slices.Equal(events, want) {
+		t.Fatalf("unexpected events:\n%q\nexpected:\n%q", events, want)
+	}
+}
+
+func TestHooksMiddlewaresMatchWalk(t *testing.T) {
+	mw := func(next http.Handler) http.Handler { return next }
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+
+	var registered [][]string
+	r := NewRouter()
+	r.Hooks(Hooks{OnRegister: func(route RegisteredRoute) {
+		registered = append(registered, route.Middlewares.Names())
+	}})
+	r.Use(Named("a", mw))
+	r.UseMatched(Named("b", mw))
+	r.With(Named("c", mw)).Route("/x", func(r Router) {
+		r.Use(Named("d", mw))
+		r.With(Named("e", mw)).Get("/y", ok)
+	})
+
+	var walked [][]string
+	Walk(r, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		walked = append(walked, Middlewares(middlewares).Names())
+		return nil
+	})
+
+	if len(registered) != 1 || len(walked) != 1 || !slices.Equal(registered[0], walked[0]) {
+		t.Fatalf("expected the middlewares reported by Walk %q, got %q", walked, registered)
+	}
+}
+
+func TestHooksOwnHooks(t *testing.T) {
+	var parent, child int
+
+	sub := NewRouter()
+	sub.Hooks(Hooks{OnMatch: func(r *http.Request) { child++ }})
+	sub.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+
+	r := NewRouter()
+	r.Mount("/sub", sub)
+	r.Hooks(Hooks{OnMatch: func(r *http.Request) { parent++ }})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/sub/", nil))
+	if parent != 0 || child != 1 {
+		t.Fatalf("expected the sub-router hooks only, got parent=%d child=%d", parent, child)
+	}
+}
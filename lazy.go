@@ -0,0 +1,131 @@
+package chi
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// MountFunc attaches the handler returned by `fn` as a subrouter along the
+// routing `pattern`, like Mount, but only calls `fn` on the first request
+// routed to it, or on Warm, for example:
+//
+//	r.MountFunc("/billing", func() http.Handler {
+//		return billing.NewRouter(db)
+//	})
+//
+// The handler is built once, and cached. Until then, the routes of the
+// subrouter are unknown to Routes and Walk, which report the `routes`
+// declared instead, if any. Once built, a subrouter inherits the NotFound,
+// MethodNotAllowed and error handlers, custom methods and hooks of the
+// router, as if it was mounted with Mount.
+func (mx *Mux) MountFunc(pattern string, fn func() http.Handler, routes ...Route) {
+	if fn == nil {
+		panic(fmt.Sprintf("chi: attempting to MountFunc() a nil handler func on '%s'", pattern))
+	}
+	mx.Mount(pattern, &lazyMount{mx: mx, pattern: pattern, fn: fn, routes: routes})
+}
+
+// Warm builds the subrouters mounted with MountFunc on the router, and on
+// its subrouters, ahead of their first request.
+func (mx *Mux) Warm() {
+	for _, route := range mx.tree.routes(mx.methodName) {
+		sub := route.SubRoutes
+		if lm, ok := sub.(*lazyMount); ok {
+			sub, _ = lm.handler().(Routes)
+		}
+		if subMux, ok := sub.(*Mux); ok {
+			subMux.Warm()
+		}
+	}
+}
+
+// lazyMount is the handler mounted by MountFunc, building the subrouter on
+// first use.
+type lazyMount struct {
+	mx      *Mux
+	pattern string
+	fn      func() http.Handler
+	routes  []Route
+
+	mu sync.Mutex
+	h  atomic.Pointer[http.Handler]
+}
+
+// handler returns the subrouter, building it on the first call.
+func (lm *lazyMount) handler() http.Handler {
+	if h := lm.built(); h != nil {
+		return h
+	}
+
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	if h := lm.built(); h != nil {
+		return h
+	}
+
+	h := lm.fn()
+	if h == nil {
+		panic(fmt.Sprintf("chi: MountFunc() on '%s' returned a nil handler", lm.pattern))
+	}
+	if subr, ok := h.(*Mux); ok {
+		lm.mx.inheritMount(subr, lm.pattern)
+	}
+	lm.h.Store(&h)
+	return h
+}
+
+// built returns the subrouter, or nil if it isn't built yet.
+func (lm *lazyMount) built() http.Handler {
+	if h := lm.h.Load(); h != nil {
+		return *h
+	}
+	return nil
+}
+
+func (lm *lazyMount) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	lm.handler().ServeHTTP(w, r)
+}
+
+// Routes returns the routes of the subrouter once built, or the declared
+// routes until then.
+func (lm *lazyMount) Routes() []Route {
+	if sub, ok := lm.built().(Routes); ok {
+		return sub.Routes()
+	}
+	return lm.routes
+}
+
+// Middlewares returns the middlewares of the subrouter once built.
+func (lm *lazyMount) Middlewares() Middlewares {
+	if sub, ok := lm.built().(Routes); ok {
+		return sub.Middlewares()
+	}
+	return nil
+}
+
+// Match builds the subrouter, and searches it for a handler matching the
+// method/path.
+func (lm *lazyMount) Match(rctx *Context, method, path string) bool {
+	return lm.Find(rctx, method, path) != ""
+}
+
+// Find builds the subrouter, and searches it for the pattern matching the
+// method/path. A handler other than a chi Router matches any path.
+func (lm *lazyMount) Find(rctx *Context, method, path string) string {
+	if sub, ok := lm.handler().(Routes); ok {
+		return sub.Find(rctx, method, path)
+	}
+	return "/*"
+}
+
+// mountedMux returns the Mux mounted as the subroutes `r`, including one
+// built by MountFunc.
+func mountedMux(r Routes) (*Mux, bool) {
+	if lm, ok := r.(*lazyMount); ok {
+		r, _ = lm.built().(Routes)
+	}
+	mx, ok := r.(*Mux)
+	return mx, ok
+}
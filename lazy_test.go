@@ -0,0 +1,142 @@
+package chi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestMountFunc(t *testing.T) {
+	var builds int
+	r := NewRouter()
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+		w.Write([]byte("custom 404"))
+	})
+	r.MountFunc("/users", func() http.Handler {
+		builds++
+		sr := NewRouter()
+		sr.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("user " + URLParam(r, "id")))
+		})
+		return sr
+	}, Route{Pattern: "/{id}", Handlers: map[string]http.Handler{"GET": nil}})
+
+	walked := func() []string {
+		var routes []string
+		Walk(r, func(method, route string, handler http.Handler, _ ...func(http.Handler) http.Handler) error {
+			routes = append(routes, method+" "+route)
+			return nil
+		})
+		return routes
+	}
+
+	if got := walked(); !slices.Equal(got, []string{"GET /users/{id}"}) {
+		t.Fatalf("expected the declared routes, got %q", got)
+	}
+	if builds != 0 {
+		t.Fatalf("expected the subrouter not to be built yet, got %d builds", builds)
+	}
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/users/1", nil))
+		if w.Body.String() != "user 1" {
+			t.Fatalf("unexpected body %q", w.Body.String())
+		}
+	}
+	if builds != 1 {
+		t.Fatalf("expected the subrouter to be built once, got %d builds", builds)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/1/nope", nil))
+	if w.Body.String() != "custom 404" {
+		t.Fatalf("expected the inherited NotFound handler, got %q", w.Body.String())
+	}
+
+	if got := walked(); !slices.Equal(got, []string{"GET /users/{id}"}) {
+		t.Fatalf("expected the built routes, got %q", got)
+	}
+	if pattern := r.Find(NewRouteContext(), "GET", "/users/2"); pattern != "/users/{id}" {
+		t.Fatalf("unexpected pattern %q", pattern)
+	}
+}
+
+func TestMountFuncConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	var builds int
+	r := NewRouter()
+	r.MountFunc("/api", func() http.Handler {
+		mu.Lock()
+		builds++
+		mu.Unlock()
+		sr := NewRouter()
+		sr.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+		return sr
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/", nil))
+		}()
+	}
+	wg.Wait()
+
+	if builds != 1 {
+		t.Fatalf("expected the subrouter to be built once, got %d builds", builds)
+	}
+}
+
+func TestMountFuncWarm(t *testing.T) {
+	var built []string
+	r := NewRouter()
+	r.Route("/v1", func(r Router) {
+		r.(*Mux).MountFunc("/a", func() http.Handler {
+			built = append(built, "a")
+			sr := NewRouter()
+			sr.MountFunc("/b", func() http.Handler {
+				built = append(built, "b")
+				return http.NotFoundHandler()
+			})
+			return sr
+		})
+	})
+
+	r.Warm()
+	if !slices.Equal(built, []string{"a", "b"}) {
+		t.Fatalf("expected all subrouters to be built, got %q", built)
+	}
+	r.Warm()
+	if len(built) != 2 {
+		t.Fatalf("expected the subrouters to be built once, got %q", built)
+	}
+}
+
+func TestMountFuncHandler(t *testing.T) {
+	var matched []string
+	r := NewRouter()
+	r.Hooks(Hooks{OnMatch: func(r *http.Request) {
+		matched = append(matched, r.Pattern)
+	}})
+	r.MountFunc("/lazy", func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	})
+	r.MountFunc("/sub", func() http.Handler {
+		sr := NewRouter()
+		sr.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+		return sr
+	})
+
+	for _, path := range []string{"/lazy/x", "/lazy", "/sub/"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	if want := []string{"/lazy/*", "/lazy", "/sub"}; !slices.Equal(matched, want) {
+		t.Fatalf("expected matches %q, got %q", want, matched)
+	}
+}
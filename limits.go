@@ -0,0 +1,110 @@
+package chi
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RouteLimits are the request body size limit, and the deadlines and timeout
+// of the routes of a router, see Mux#Limits. A zero field leaves the limit
+// to the parent router, and a negative one removes it.
+type RouteLimits struct {
+	// MaxBodyBytes limits the size of the request body, with
+	// http.MaxBytesReader.
+	MaxBodyBytes int64
+
+	// ReadTimeout and WriteTimeout set the read and write deadlines of the
+	// connection, from the time the route is matched, with
+	// http.ResponseController. They are ignored by a http.ResponseWriter
+	// which doesn't support deadlines.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// HandlerTimeout cancels the request context after the timeout, and
+	// responds with 504 Gateway Timeout if the handler returns because of
+	// it, as middleware.Timeout does.
+	HandlerTimeout time.Duration
+}
+
+// Limits sets the request limits of the routes of the router, applied once a
+// request matches a route, for example:
+//
+//	r.Limits(chi.RouteLimits{MaxBodyBytes: 1 << 20, HandlerTimeout: 10 * time.Second})
+//
+//	r.Group(func(r chi.Router) {
+//		r.(*chi.Mux).Limits(chi.RouteLimits{MaxBodyBytes: 100 << 20, HandlerTimeout: -1})
+//		r.Post("/uploads", upload)
+//	})
+//
+// The limits of a router are the defaults of the sub-routers mounted on it,
+// which override them field by field with their own limits, and the limits
+// of an inline group, created with Group or With, override the limits of its
+// router for the routes of the group.
+func (mx *Mux) Limits(limits RouteLimits) {
+	mx.limits = &limits
+}
+
+// groupLimits returns `limits` overridden by the limits of the inline
+// groups the route was registered through, outermost first.
+func (mx *Mux) groupLimits(limits RouteLimits) RouteLimits {
+	if mx == nil || !mx.inline {
+		return limits
+	}
+	return mx.parent.groupLimits(limits).merge(mx.limits)
+}
+
+// merge returns the limits overridden by the non-zero fields of `o`.
+func (l RouteLimits) merge(o *RouteLimits) RouteLimits {
+	if o == nil {
+		return l
+	}
+	if o.MaxBodyBytes != 0 {
+		l.MaxBodyBytes = o.MaxBodyBytes
+	}
+	if o.ReadTimeout != 0 {
+		l.ReadTimeout = o.ReadTimeout
+	}
+	if o.WriteTimeout != 0 {
+		l.WriteTimeout = o.WriteTimeout
+	}
+	if o.HandlerTimeout != 0 {
+		l.HandlerTimeout = o.HandlerTimeout
+	}
+	return l
+}
+
+// handler returns `h` served within the limits, or `h` itself if there are
+// no limits.
+func (l RouteLimits) handler(h http.Handler) http.Handler {
+	if l.MaxBodyBytes <= 0 && l.ReadTimeout <= 0 && l.WriteTimeout <= 0 && l.HandlerTimeout <= 0 {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l.MaxBodyBytes > 0 && r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, l.MaxBodyBytes)
+		}
+
+		rc := http.NewResponseController(w)
+		if l.ReadTimeout > 0 {
+			_ = rc.SetReadDeadline(time.Now().Add(l.ReadTimeout))
+		}
+		if l.WriteTimeout > 0 {
+			_ = rc.SetWriteDeadline(time.Now().Add(l.WriteTimeout))
+		}
+
+		if l.HandlerTimeout > 0 {
+			ctx, cancel := context.WithTimeout(r.Context(), l.HandlerTimeout)
+			defer func() {
+				cancel()
+				if ctx.Err() == context.DeadlineExceeded {
+					w.WriteHeader(http.StatusGatewayTimeout)
+				}
+			}()
+			r = r.WithContext(ctx)
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
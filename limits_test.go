@@ -0,0 +1,123 @@
+package chi
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLimits(t *testing.T) {
+	readBody := func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		fmt.Fprintf(w, "%d", len(body))
+	}
+
+	r := NewRouter()
+	r.Limits(RouteLimits{MaxBodyBytes: 10})
+	r.Post("/small", readBody)
+	r.Group(func(r Router) {
+		r.(*Mux).Limits(RouteLimits{MaxBodyBytes: 100})
+		r.Post("/upload", readBody)
+		r.With().Post("/nested", readBody)
+	})
+	r.Route("/api", func(r Router) {
+		r.Post("/inherited", readBody)
+		r.Route("/unlimited", func(r Router) {
+			r.(*Mux).Limits(RouteLimits{MaxBodyBytes: -1})
+			r.Post("/", readBody)
+		})
+	})
+	r.Mount("/raw", http.HandlerFunc(readBody))
+	r.MountFunc("/lazy", func() http.Handler { return http.HandlerFunc(readBody) })
+
+	tests := []struct {
+		path string
+		size int
+		code int
+	}{
+		{"/small", 10, 200},
+		{"/small", 11, 413},
+		{"/upload", 100, 200},
+		{"/upload", 101, 413},
+		{"/nested", 100, 200},
+		{"/api/inherited", 11, 413},
+		{"/api/unlimited", 1000, 200},
+		{"/raw/x", 11, 413},
+		{"/lazy/x", 10, 200},
+		{"/lazy/x", 11, 413},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", tt.path, strings.NewReader(strings.Repeat("x", tt.size))))
+		if w.Code != tt.code {
+			t.Errorf("POST %s with %d bytes: expected status %d, got %d %q", tt.path, tt.size, tt.code, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestLimitsHandlerTimeout(t *testing.T) {
+	r := NewRouter()
+	r.Limits(RouteLimits{HandlerTimeout: 10 * time.Millisecond})
+	r.Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	r.Get("/deadline", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); !ok {
+			t.Error("expected a deadline on the request context")
+		}
+	})
+	r.With().Group(func(r Router) {
+		r.(*Mux).Limits(RouteLimits{HandlerTimeout: -1})
+		r.Get("/stream", func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := r.Context().Deadline(); ok {
+				t.Error("expected no deadline on the request context")
+			}
+		})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/slow", nil))
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected status 504, got %d", w.Code)
+	}
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/deadline", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/stream", nil))
+}
+
+func TestLimitsDeadlines(t *testing.T) {
+	r := NewRouter()
+	r.Limits(RouteLimits{WriteTimeout: 50 * time.Millisecond})
+	r.Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("late"))
+	})
+	r.Get("/fast", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	if _, body := testRequest(t, ts, "GET", "/fast", nil); body != "ok" {
+		t.Fatalf("unexpected body %q", body)
+	}
+
+	// The write deadline fails the response of the slow route.
+	resp, err := http.Get(ts.URL + "/slow")
+	if err == nil {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err == nil && string(body) == "late" {
+			t.Fatal("expected the write deadline to fail the response")
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package chi
+
+import "net/http"
+
+// Metadata holds descriptive values attached to a route handler, such as
+// documentation details or the request and response types of the handler,
+// keyed by name. See WithMetadata.
+type Metadata map[string]any
+
+// Metadata keys set by chi's own handlers.
+const (
+	// MetaRequestType is the reflect.Type a handler decodes requests into.
+	MetaRequestType = "chi.requestType"
+
+	// MetaResponseType is the reflect.Type a handler encodes responses from.
+	MetaResponseType = "chi.responseType"
+
+	// MetaVersions are the API versions a route is registered for, see
+	// Mux#Version.
+	MetaVersions = "chi.versions"
+
+	// MetaRedirectTo and MetaRedirectCode are the target and status code of
+	// a route registered with Redirect.
+	MetaRedirectTo   = "chi.redirectTo"
+	MetaRedirectCode = "chi.redirectCode"
+
+	// MetaAliasOf is the canonical pattern of a route registered with Alias.
+	MetaAliasOf = "chi.aliasOf"
+)
+
+// MetadataHandler is a http.Handler carrying Metadata about the route it is
+// registered on.
+type MetadataHandler interface {
+	http.Handler
+	Metadata() Metadata
+}
+
+// WithMetadata returns a http.Handler that serves `h` and carries the
+// metadata `md`, merged over any metadata already carried by `h`.
+//
+// Register the returned handler with Handle or Method, as the metadata is
+// lost when converting it to a http.HandlerFunc, for example:
+//
+//	r.Method("GET", "/users", chi.WithMetadata(listUsers, chi.Metadata{
+//		"summary": "List all users",
+//	}))
+func WithMetadata(h http.Handler, md Metadata) http.Handler {
+	return &metadataHandler{h, md}
+}
+
+// HandlerMetadata returns the Metadata carried by a route handler, looking
+// through the inline middleware chain of a ChainHandler. It returns nil if
+// the handler carries no metadata.
+func HandlerMetadata(h http.Handler) Metadata {
+	if chain, ok := h.(*ChainHandler); ok {
+		h = chain.Endpoint
+	}
+	if mh, ok := h.(MetadataHandler); ok {
+		return mh.Metadata()
+	}
+	return nil
+}
+
+type metadataHandler struct {
+	handler http.Handler
+	md      Metadata
+}
+
+func (h *metadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.handler.ServeHTTP(w, r)
+}
+
+func (h *metadataHandler) Metadata() Metadata {
+	inner := HandlerMetadata(h.handler)
+	md := make(Metadata, len(inner)+len(h.md))
+	for k, v := range inner {
+		md[k] = v
+	}
+	for k, v := range h.md {
+		md[k] = v
+	}
+	return md
+}
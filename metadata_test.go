@@ -0,0 +1,23 @@
+package chi
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestHandlerMetadata(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	if md := HandlerMetadata(h); md != nil {
+		t.Fatalf("expected no metadata, got %v", md)
+	}
+
+	inner := WithMetadata(h, Metadata{"summary": "inner", "tag": "users"})
+	outer := WithMetadata(inner, Metadata{"summary": "outer"})
+	chained := Chain(func(next http.Handler) http.Handler { return next }).Handler(outer)
+
+	md := HandlerMetadata(chained)
+	if md["summary"] != "outer" || md["tag"] != "users" {
+		t.Fatalf("unexpected metadata %v", md)
+	}
+}
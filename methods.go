@@ -0,0 +1,85 @@
+package chi
+
+import (
+	"slices"
+	"strings"
+)
+
+// customMethodShift is the bit offset of the index of a custom method in
+// its methodTyp, past the bits of the standard methods.
+const customMethodShift = 11
+
+// mUNKNOWN is the method type of a method unknown to a router, which only
+// routes it to the sub-routers mounted on it, as they may know the method.
+const mUNKNOWN = mCUSTOM
+
+// RegisterMethod adds support for the custom HTTP `methods` on the router,
+// available via Router#Method and Router#MethodFunc, for example:
+//
+//	r := chi.NewRouter()
+//	r.RegisterMethod("PROPFIND", "MKCOL")
+//	r.MethodFunc("PROPFIND", "/files/*", propfind)
+//
+// Unlike the package-level RegisterMethod, the methods are only known to the
+// router, and to the sub-routers mounted on it, so routers registering
+// methods concurrently, or libraries registering the same method, don't
+// interfere with each other. There is no limit on the number of methods.
+//
+// A route registered with Handle, or a mounted handler, serves the custom
+// methods of the router too. A method registered on a sub-router only is
+// routed to it by the parent routers, but isn't served by their own routes.
+func (mx *Mux) RegisterMethod(methods ...string) {
+	tm := mx.treeMux()
+	for _, method := range methods {
+		if method == "" {
+			continue
+		}
+		method = strings.ToUpper(method)
+		if _, ok := tm.methodTyp(method); ok {
+			continue
+		}
+		if tm.methods == nil {
+			tm.methods = map[string]methodTyp{}
+			tm.methodNames = map[methodTyp]string{}
+		}
+		mt := mCUSTOM | methodTyp(len(tm.methods)+1)<<customMethodShift
+		tm.methods[method] = mt
+		tm.methodNames[mt] = method
+	}
+
+	tm.updateSubRoutes(func(subMux *Mux) {
+		subMux.RegisterMethod(methods...)
+	})
+}
+
+// methodTyp returns the method type of the HTTP `method` on the router.
+func (mx *Mux) methodTyp(method string) (methodTyp, bool) {
+	if mt, ok := methodMap[method]; ok {
+		return mt, true
+	}
+	mt, ok := mx.treeMux().methods[method]
+	return mt, ok
+}
+
+// methodName returns the name of the HTTP method of the method type `mt` on
+// the router.
+func (mx *Mux) methodName(mt methodTyp) string {
+	if m, ok := reverseMethodMap[mt]; ok {
+		return m
+	}
+	if mx == nil {
+		return ""
+	}
+	return mx.treeMux().methodNames[mt]
+}
+
+// customMethods returns the custom HTTP methods registered on the router,
+// sorted.
+func (mx *Mux) customMethods() []string {
+	methods := []string{}
+	for m := range mx.treeMux().methods {
+		methods = append(methods, m)
+	}
+	slices.Sort(methods)
+	return methods
+}
@@ -0,0 +1,145 @@
+package chi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMuxRegisterMethod(t *testing.T) {
+	r := NewRouter()
+	r.RegisterMethod("propfind", "MKCOL")
+	r.MethodFunc("PROPFIND", "/files/*", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("propfind " + URLParam(r, "*")))
+	})
+	r.Get("/files/*", func(w http.ResponseWriter, r *http.Request) {})
+	r.Handle("/any", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("any " + r.Method))
+	}))
+	r.Route("/dav", func(r Router) {
+		r.MethodFunc("MKCOL", "/{name}", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("mkcol " + URLParam(r, "name")))
+		})
+	})
+
+	tests := []struct {
+		method, path string
+		code         int
+		body, allow  string
+	}{
+		{"PROPFIND", "/files/a/b", 200, "propfind a/b", ""},
+		{"MKCOL", "/files/a", 405, "", "GET,PROPFIND"},
+		{"MKCOL", "/any", 200, "any MKCOL", ""},
+		{"MKCOL", "/dav/x", 200, "mkcol x", ""},
+		{"COPY", "/any", 405, "", ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.code || w.Body.String() != tt.body {
+			t.Errorf("%s %s: expected %d %q, got %d %q", tt.method, tt.path, tt.code, tt.body, w.Code, w.Body.String())
+		}
+		allow := w.Header().Values("Allow")
+		slices.Sort(allow)
+		if got := strings.Join(allow, ","); got != tt.allow {
+			t.Errorf("%s %s: expected Allow %q, got %q", tt.method, tt.path, tt.allow, got)
+		}
+	}
+
+	var walked []string
+	Walk(r, func(method, route string, handler http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if route != "/any" {
+			walked = append(walked, method+" "+route)
+		}
+		return nil
+	})
+	slices.Sort(walked)
+	want := []string{"GET /files/*", "MKCOL /dav/{name}", "PROPFIND /files/*"}
+	if !slices.Equal(walked, want) {
+		t.Fatalf("expected routes %q, got %q", want, walked)
+	}
+
+	// The methods of a router are unknown to the other routers.
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic()")
+		}
+	}()
+	NewRouter().MethodFunc("PROPFIND", "/", func(w http.ResponseWriter, r *http.Request) {})
+}
+
+func TestMuxRegisterMethodSubRouter(t *testing.T) {
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Method + " " + RouteContext(r.Context()).RoutePattern()))
+	}
+
+	sub := NewRouter()
+	sub.RegisterMethod("PROPFIND")
+	sub.MethodFunc("PROPFIND", "/x", h)
+
+	r := NewRouter()
+	r.Handle("/any", http.HandlerFunc(h))
+	r.Mount("/dav", sub)
+	r.Route("/files", func(r Router) {
+		r.(*Mux).RegisterMethod("MKCOL")
+		r.MethodFunc("MKCOL", "/{name}", h)
+	})
+
+	tests := []struct {
+		method, path string
+		code         int
+		body         string
+	}{
+		{"PROPFIND", "/dav/x", 200, "PROPFIND /dav/x"},
+		{"MKCOL", "/files/a", 200, "MKCOL /files/{name}"},
+		{"PROPFIND", "/files/a", 405, ""},
+		{"MKCOL", "/dav/x", 405, ""},
+		{"PROPFIND", "/any", 405, ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.code || w.Body.String() != tt.body {
+			t.Errorf("%s %s: expected %d %q, got %d %q", tt.method, tt.path, tt.code, tt.body, w.Code, w.Body.String())
+		}
+	}
+
+	if pattern := r.Find(NewRouteContext(), "PROPFIND", "/dav/x"); pattern != "/dav/x" {
+		t.Fatalf("unexpected pattern %q", pattern)
+	}
+	if _, pattern, _ := r.Handler(httptest.NewRequest("MKCOL", "/files/a", nil)); pattern != "/files/{name}" {
+		t.Fatalf("unexpected pattern %q", pattern)
+	}
+}
+
+func TestMuxRegisterMethodParallel(t *testing.T) {
+	for i := 0; i < 8; i++ {
+		method := fmt.Sprintf("METHOD%d", i)
+		t.Run(method, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewRouter()
+			for j := 0; j < 100; j++ {
+				r.RegisterMethod(fmt.Sprintf("EXTRA%d", j))
+			}
+			r.RegisterMethod(method)
+			r.MethodFunc(method, "/", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(r.Method))
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(method, "/", nil))
+			if w.Body.String() != method {
+				t.Fatalf("expected body %q, got %q", method, w.Body.String())
+			}
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("EXTRA99", "/", nil))
+			if w.Code != 405 || w.Header().Get("Allow") != method {
+				t.Fatalf("expected 405 allowing %s, got %d %q", method, w.Code, w.Header().Get("Allow"))
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package middleware
+
+import (
+	"cmp"
+	"maps"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// DeprecationOpts represents the deprecation lifecycle of a route, see
+// Deprecated.
+type DeprecationOpts struct {
+	// At is when the route was deprecated, sent in the RFC 9745 Deprecation
+	// header. It's required.
+	At time.Time
+
+	// Sunset is when the route becomes unavailable, sent in the RFC 8594
+	// Sunset header. It's optional.
+	Sunset time.Time
+
+	// Link is the URL of documentation about the deprecation, sent in a
+	// `Link: <...>; rel="deprecation"` header. It's optional.
+	Link string
+
+	// Gone responds to requests with 410 Gone once the sunset date is
+	// reached, instead of serving the route.
+	Gone bool
+
+	// Tracker records the calls to the deprecated routes.
+	Tracker *DeprecationTracker
+
+	// OnCall is called for every call to a deprecated route, for example to
+	// log the clients still relying on it.
+	OnCall func(r *http.Request, call DeprecatedCall)
+
+	// ClientKey identifies the client of a request. It defaults to the
+	// client IP set by the ClientIPFrom* middlewares, or else to the host
+	// of the request RemoteAddr.
+	ClientKey func(r *http.Request) string
+}
+
+// DeprecatedCall is a call to a deprecated route.
+type DeprecatedCall struct {
+	// Method and Pattern identify the route called.
+	Method  string
+	Pattern string
+
+	// Client identifies the caller, see DeprecationOpts.ClientKey.
+	Client string
+
+	// RequestID is the ID of the request, as set by the RequestID
+	// middleware.
+	RequestID string
+
+	// Time is when the call was made.
+	Time time.Time
+
+	// Gone reports whether the call was made after the sunset date and
+	// responded to with 410 Gone.
+	Gone bool
+}
+
+// Deprecated is a middleware marking the routes it is registered on as
+// deprecated. It sets the RFC 9745 Deprecation header, in its structured
+// "@<unix time>" form, along with the Sunset and `Link; rel="deprecation"`
+// headers, and reports the calls to the routes to a DeprecationTracker and
+// the OnCall callback. For example:
+//
+//	tracker := middleware.NewDeprecationTracker()
+//
+//	r.With(middleware.Deprecated(middleware.DeprecationOpts{
+//		At:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+//		Sunset:  time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+//		Link:    "https://example.com/changelog#v1",
+//		Gone:    true,
+//		Tracker: tracker,
+//	})).Get("/v1/users", listUsersV1)
+//
+// Register it with With or UseMatched, or within the sub-router of the
+// routes, so the full pattern of the routes is known to it.
+//
+// Unlike Sunset, which writes the Deprecation header as a HTTP-date,
+// Deprecated follows RFC 9745.
+func Deprecated(opts DeprecationOpts) func(http.Handler) http.Handler {
+	if opts.At.IsZero() {
+		panic("chi/middleware: Deprecated expects a deprecation date")
+	}
+	if opts.ClientKey == nil {
+		opts.ClientKey = clientKey
+	}
+
+	deprecation := "@" + strconv.FormatInt(opts.At.Unix(), 10)
+	var sunset, link string
+	if !opts.Sunset.IsZero() {
+		sunset = opts.Sunset.UTC().Format(http.TimeFormat)
+	}
+	if opts.Link != "" {
+		link = "<" + opts.Link + `>; rel="deprecation"; type="text/html"`
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+
+			h := w.Header()
+			h.Set("Deprecation", deprecation)
+			if sunset != "" {
+				h.Set("Sunset", sunset)
+			}
+			if link != "" {
+				h.Add("Link", link)
+			}
+
+			call := DeprecatedCall{
+				Method:    r.Method,
+				Client:    opts.ClientKey(r),
+				RequestID: GetReqID(r.Context()),
+				Time:      now,
+				Gone:      opts.Gone && !opts.Sunset.IsZero() && !now.Before(opts.Sunset),
+			}
+
+			if call.Gone {
+				http.Error(w, http.StatusText(http.StatusGone), http.StatusGone)
+			} else {
+				next.ServeHTTP(w, r)
+			}
+
+			// Read the pattern once the request is served, as it is only
+			// complete once routing reached the endpoint.
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				call.Pattern = rctx.RoutePattern()
+				if rctx.RouteMethod != "" {
+					call.Method = rctx.RouteMethod
+				}
+			}
+			if opts.Tracker != nil {
+				opts.Tracker.record(call)
+			}
+			if opts.OnCall != nil {
+				opts.OnCall(r, call)
+			}
+		})
+	}
+}
+
+// clientKey returns the client IP of the request.
+func clientKey(r *http.Request) string {
+	if ip := GetClientIP(r.Context()); ip != "" {
+		return ip
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// DeprecationTracker records the calls to deprecated routes, per route and
+// per client. It's safe for concurrent use.
+type DeprecationTracker struct {
+	mu     sync.Mutex
+	routes map[string]*DeprecatedUsage
+}
+
+// NewDeprecationTracker returns a new DeprecationTracker.
+func NewDeprecationTracker() *DeprecationTracker {
+	return &DeprecationTracker{routes: map[string]*DeprecatedUsage{}}
+}
+
+// DeprecatedUsage is the usage of a deprecated route.
+type DeprecatedUsage struct {
+	Method  string
+	Pattern string
+
+	// Hits is the number of calls to the route, including the calls
+	// responded to with 410 Gone.
+	Hits int
+
+	// Clients is the number of calls to the route, by client.
+	Clients map[string]int
+
+	// LastCall is when the route was last called.
+	LastCall time.Time
+}
+
+func (t *DeprecationTracker) record(call DeprecatedCall) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := call.Method + " " + call.Pattern
+	u := t.routes[key]
+	if u == nil {
+		u = &DeprecatedUsage{Method: call.Method, Pattern: call.Pattern, Clients: map[string]int{}}
+		t.routes[key] = u
+	}
+	u.Hits++
+	u.Clients[call.Client]++
+	if call.Time.After(u.LastCall) {
+		u.LastCall = call.Time
+	}
+}
+
+// Usage returns a copy of the usage of the deprecated routes called so far,
+// sorted by pattern and method.
+func (t *DeprecationTracker) Usage() []DeprecatedUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := make([]DeprecatedUsage, 0, len(t.routes))
+	for _, u := range t.routes {
+		c := *u
+		c.Clients = maps.Clone(u.Clients)
+		usage = append(usage, c)
+	}
+	slices.SortFunc(usage, func(a, b DeprecatedUsage) int {
+		return cmp.Or(strings.Compare(a.Pattern, b.Pattern), strings.Compare(a.Method, b.Method))
+	})
+	return usage
+}
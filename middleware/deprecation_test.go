@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestDeprecated(t *testing.T) {
+	tracker := NewDeprecationTracker()
+	var calls []DeprecatedCall
+
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := chi.NewRouter()
+	r.Use(RequestID)
+	r.Route("/v1", func(r chi.Router) {
+		r.Use(Deprecated(DeprecationOpts{
+			At:      at,
+			Sunset:  time.Now().Add(time.Hour),
+			Link:    "https://example.com/deprecation",
+			Tracker: tracker,
+			Gone:    true,
+			OnCall: func(r *http.Request, call DeprecatedCall) {
+				calls = append(calls, call)
+			},
+		}))
+		r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("user"))
+		})
+	})
+	r.With(Deprecated(DeprecationOpts{
+		At:      at,
+		Sunset:  time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		Tracker: tracker,
+		Gone:    true,
+	})).Get("/legacy", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("legacy"))
+	})
+
+	for _, remoteAddr := range []string{"10.0.0.1:1234", "10.0.0.1:4321", "10.0.0.2:1234"} {
+		req := httptest.NewRequest("GET", "/v1/users/1", nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != 200 || w.Body.String() != "user" {
+			t.Fatalf("unexpected response %d %q", w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("Deprecation"); got != "@1735689600" {
+			t.Fatalf("unexpected Deprecation header %q", got)
+		}
+		if got := w.Header().Get("Sunset"); got == "" {
+			t.Fatal("expected a Sunset header")
+		}
+		if got := w.Header().Get("Link"); got != `<https://example.com/deprecation>; rel="deprecation"; type="text/html"` {
+			t.Fatalf("unexpected Link header %q", got)
+		}
+	}
+
+	// The route is gone once its sunset date is reached.
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/legacy", nil))
+	if w.Code != http.StatusGone {
+		t.Fatalf("expected status 410, got %d", w.Code)
+	}
+	if got := w.Header().Get("Sunset"); got != "Sun, 01 Jun 2025 00:00:00 GMT" {
+		t.Fatalf("unexpected Sunset header %q", got)
+	}
+
+	if len(calls) != 3 {
+		t.Fatalf("expected 3 calls, got %d", len(calls))
+	}
+	if c := calls[0]; c.Method != "GET" || c.Pattern != "/v1/users/{id}" || c.Client != "10.0.0.1" || c.RequestID == "" || c.Gone {
+		t.Fatalf("unexpected call %+v", c)
+	}
+
+	usage := tracker.Usage()
+	if len(usage) != 2 {
+		t.Fatalf("expected 2 routes, got %+v", usage)
+	}
+	if u := usage[0]; u.Pattern != "/legacy" || u.Hits != 1 {
+		t.Fatalf("unexpected usage %+v", u)
+	}
+	if u := usage[1]; u.Pattern != "/v1/users/{id}" || u.Hits != 3 || u.Clients["10.0.0.1"] != 2 || u.Clients["10.0.0.2"] != 1 {
+		t.Fatalf("unexpected usage %+v", u)
+	}
+}
+
+func TestDeprecatedClientKey(t *testing.T) {
+	var client string
+	h := Deprecated(DeprecationOpts{
+		At: time.Now(),
+		ClientKey: func(r *http.Request) string {
+			return r.Header.Get("X-Api-Key")
+		},
+		OnCall: func(r *http.Request, call DeprecatedCall) {
+			client = call.Client
+		},
+	})(http.NotFoundHandler())
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Api-Key", "team-a")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if client != "team-a" {
+		t.Fatalf("unexpected client %q", client)
+	}
+}
@@ -8,6 +8,7 @@ import (
 // Sunset set Deprecation/Sunset header to response
 // This can be used to enable Sunset in a route or a route group
 // For more: https://www.rfc-editor.org/rfc/rfc8594.html
+// See Deprecated for RFC 9745 Deprecation headers and usage tracking.
 func Sunset(sunsetAt time.Time, links ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
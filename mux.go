@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 )
@@ -18,10 +19,15 @@ var _ Router = &Mux{}
 // modular and composable HTTP services with a large set of handlers. It's
 // particularly useful for writing large REST API services that break a handler
 // into many smaller parts composed of middlewares and end handlers.
+//
+// The Router interface only has the core routing methods of Mux. The others,
+// like ErrorHandler, Limits or Version, are reached through a type assertion
+// on the Router of a Group or Route, as in r.(*chi.Mux).Limits(limits).
 type Mux struct {
 	// The computed mux handler made of the chained middleware stack and
-	// the tree router
-	handler http.Handler
+	// the tree router, built once on the first request
+	handler   http.Handler
+	buildOnce sync.Once
 
 	// The radix trie router
 	tree *node
@@ -39,9 +45,54 @@ type Mux struct {
 	// Custom route not found handler
 	notFoundHandler http.HandlerFunc
 
+	// Custom error handler for HandlerFuncE handlers
+	errorHandler ErrorHandlerFunc
+
 	// The middleware stack
 	middlewares []func(http.Handler) http.Handler
 
+	// The middleware stack executed after a route has been matched
+	matchedMiddlewares []func(http.Handler) http.Handler
+
+	// Inline groups with their own NotFound or MethodNotAllowed handlers,
+	// registered on the mux owning the routing tree
+	groups []*Mux
+
+	// The route prefix shared by all routes of an inline group, along with
+	// whether any route has been registered through the group, or the
+	// router, yet
+	groupPrefix string
+	hasRoutes   bool
+
+	// The API version of the routes registered through an inline group,
+	// see Version
+	version string
+
+	// The routes registered for several versions, by pattern and method,
+	// on the mux owning the routing tree
+	versionRoutes map[string]map[methodTyp]*versionHandler
+
+	// The lifecycle hooks of the router, along with the route prefix and
+	// middlewares of the parent routers when inherited through a mount
+	hooks            *Hooks
+	hooksInherited   bool
+	hooksPrefix      string
+	hooksMiddlewares Middlewares
+	hooksMatched     Middlewares
+
+	// The request limits of the routes of the router, or of an inline group
+	limits *RouteLimits
+
+	// The custom HTTP methods registered on the router, by name and by
+	// method type, see RegisterMethod
+	methods     map[string]methodTyp
+	methodNames map[methodTyp]string
+
+	// Whether the router is in strict mode, see Strict, along with the
+	// first route registered, which prevents adding middlewares with Use
+	strict   bool
+	routedAt string
+
 	// Controls the behaviour of middleware chain generation when a mux
 	// is registered as an inline group inside another mux.
 	inline bool
@@ -62,7 +113,8 @@ func NewMux() *Mux {
 // reuse routing contexts for each request.
 func (mx *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Ensure the mux has some routes defined on the mux
-	if mx.handler == nil {
+	h := mx.routeHandler()
+	if h == nil {
 		mx.NotFoundHandler().ServeHTTP(w, r)
 		return
 	}
@@ -70,7 +122,7 @@ func (mx *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Check if a routing context already exists from a parent router.
 	rctx, _ := r.Context().Value(RouteCtxKey).(*Context)
 	if rctx != nil {
-		mx.handler.ServeHTTP(w, r)
+		h.ServeHTTP(w, r)
 		return
 	}
 
@@ -87,7 +139,7 @@ func (mx *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	r = r.WithContext(context.WithValue(r.Context(), RouteCtxKey, rctx))
 
 	// Serve the request and once its done, put the request context back in the sync pool
-	mx.handler.ServeHTTP(w, r)
+	h.ServeHTTP(w, r)
 	mx.pool.Put(rctx)
 }
 
@@ -97,13 +149,45 @@ func (mx *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 // route to a specific handler, which provides opportunity to respond early,
 // change the course of the request execution, or set request-scoped values for
 // the next http.Handler.
+//
+// Middlewares may be added after routes, until the mux serves its first
+// request, which builds the handler chain. Only the inline middlewares of a
+// Group or With inline-Mux must be defined before its routes. Note that the
+// OnRegister hook reports the middlewares of a route as they were when the
+// route was registered.
 func (mx *Mux) Use(middlewares ...func(http.Handler) http.Handler) {
+	if mx.inline && mx.handler != nil {
+		panic("chi: all middlewares must be defined before routes on an inline mux")
+	}
 	if mx.handler != nil {
-		panic("chi: all middlewares must be defined before routes on a mux")
+		panic("chi: all middlewares must be defined before the mux serves requests")
+	}
+	if mx.strict && mx.routedAt != "" {
+		panic(fmt.Sprintf("chi: Use() at %s after %s; all middlewares must be defined before routes on a strict mux", callerSite(), mx.routedAt))
 	}
 	mx.middlewares = append(mx.middlewares, middlewares...)
 }
 
+// UseMatched appends a middleware handler to the stack of middlewares
+// executed once the routing tree has matched a route, and only then.
+//
+// Unlike middlewares registered with Use, these have the final routing
+// pattern, URL params and route metadata available in the routing Context
+// before calling the next handler, and are skipped for requests answered
+// with the NotFound or MethodNotAllowed handlers. For routes of mounted
+// sub-routers, they run after the sub-router has matched the route, and
+// before the middlewares of the sub-router's own UseMatched stack.
+//
+// On an inline-Mux, like a Group(), this is the same as Use, as inline
+// middlewares always execute after the route has been matched.
+func (mx *Mux) UseMatched(middlewares ...func(http.Handler) http.Handler) {
+	if mx.inline {
+		mx.Use(middlewares...)
+		return
+	}
+	mx.matchedMiddlewares = append(mx.matchedMiddlewares, middlewares...)
+}
+
 // Handle adds the route `pattern` that matches any http method to
 // execute the `handler` http.Handler.
 func (mx *Mux) Handle(pattern string, handler http.Handler) {
@@ -125,7 +209,7 @@ func (mx *Mux) HandleFunc(pattern string, handlerFn http.HandlerFunc) {
 // Method adds the route `pattern` that matches `method` http method to
 // execute the `handler` http.Handler.
 func (mx *Mux) Method(method, pattern string, handler http.Handler) {
-	m, ok := methodMap[strings.ToUpper(method)]
+	m, ok := mx.methodTyp(strings.ToUpper(method))
 	if !ok {
 		panic(fmt.Sprintf("chi: '%s' http method is not supported.", method))
 	}
@@ -200,52 +284,88 @@ func (mx *Mux) Trace(pattern string, handlerFn http.HandlerFunc) {
 
 // NotFound sets a custom http.HandlerFunc for routing paths that could
 // not be found. The default 404 handler is `http.NotFound`.
+//
+// When called on an inline group, the handler only responds for paths that
+// fall under the route prefix shared by the group's routes, see Group().
 func (mx *Mux) NotFound(handlerFn http.HandlerFunc) {
-	// Build NotFound handler chain
-	m := mx
-	hFn := handlerFn
 	if mx.inline && mx.parent != nil {
-		m = mx.parent
-		hFn = Chain(mx.middlewares...).HandlerFunc(hFn).ServeHTTP
+		mx.notFoundHandler = Chain(mx.middlewares...).HandlerFunc(handlerFn).ServeHTTP
+		mx.treeMux().addGroup(mx)
+		return
 	}
 
 	// Update the notFoundHandler from this point forward
-	m.notFoundHandler = hFn
-	m.updateSubRoutes(func(subMux *Mux) {
+	mx.notFoundHandler = handlerFn
+	mx.updateSubRoutes(func(subMux *Mux) {
 		if subMux.notFoundHandler == nil {
-			subMux.NotFound(hFn)
+			subMux.NotFound(handlerFn)
 		}
 	})
 }
 
 // MethodNotAllowed sets a custom http.HandlerFunc for routing paths where the
 // method is unresolved. The default handler returns a 405 with an empty body.
+//
+// When called on an inline group, the handler only responds for paths that
+// fall under the route prefix shared by the group's routes, see Group().
 func (mx *Mux) MethodNotAllowed(handlerFn http.HandlerFunc) {
-	// Build MethodNotAllowed handler chain
-	m := mx
-	hFn := handlerFn
 	if mx.inline && mx.parent != nil {
-		m = mx.parent
-		hFn = Chain(mx.middlewares...).HandlerFunc(hFn).ServeHTTP
+		mx.methodNotAllowedHandler = Chain(mx.middlewares...).HandlerFunc(handlerFn).ServeHTTP
+		mx.treeMux().addGroup(mx)
+		return
 	}
 
 	// Update the methodNotAllowedHandler from this point forward
-	m.methodNotAllowedHandler = hFn
-	m.updateSubRoutes(func(subMux *Mux) {
+	mx.methodNotAllowedHandler = handlerFn
+	mx.updateSubRoutes(func(subMux *Mux) {
 		if subMux.methodNotAllowedHandler == nil {
-			subMux.MethodNotAllowed(hFn)
+			subMux.MethodNotAllowed(handlerFn)
+		}
+	})
+}
+
+// ErrorHandler sets a custom ErrorHandlerFunc to respond with whenever a
+// HandlerFuncE handler of the router returns an error. The default handler
+// is DefaultErrorHandler.
+//
+// Mounted sub-routers without an error handler of their own inherit the
+// handler, and when called on an inline group, the handler only applies to
+// the routes of the group, for example:
+//
+//	r.Group(func(r chi.Router) {
+//		r.(*chi.Mux).ErrorHandler(jsonError)
+//	})
+func (mx *Mux) ErrorHandler(handlerFn ErrorHandlerFunc) {
+	mx.errorHandler = handlerFn
+	if mx.inline {
+		return
+	}
+	mx.updateSubRoutes(func(subMux *Mux) {
+		if subMux.errorHandler == nil {
+			subMux.ErrorHandler(handlerFn)
 		}
 	})
 }
 
 // With adds inline middlewares for an endpoint handler.
 func (mx *Mux) With(middlewares ...func(http.Handler) http.Handler) Router {
-	// Similarly as in handle(), we must build the mux handler once additional
-	// middleware registration isn't allowed for this stack, like now.
-	if !mx.inline && mx.handler == nil {
-		mx.updateRouteHandler()
+	if mx.treeMux().strict {
+		site := callerSite()
+		if len(middlewares) == 0 {
+			panic(fmt.Sprintf("chi: With() at %s without middlewares", site))
+		}
+		for _, mw := range middlewares {
+			if mw == nil {
+				panic(fmt.Sprintf("chi: With() at %s with a nil middleware", site))
+			}
+		}
 	}
+	return mx.with(middlewares...)
+}
 
+// with returns an inline mux with the middlewares of the mux followed by
+// `middlewares`.
+func (mx *Mux) with(middlewares ...func(http.Handler) http.Handler) *Mux {
 	// Copy middlewares from parent inline muxs
 	var mws Middlewares
 	if mx.inline {
@@ -257,6 +377,7 @@ func (mx *Mux) With(middlewares ...func(http.Handler) http.Handler) Router {
 	im := &Mux{
 		pool: mx.pool, inline: true, parent: mx, tree: mx.tree, middlewares: mws,
 		notFoundHandler: mx.notFoundHandler, methodNotAllowedHandler: mx.methodNotAllowedHandler,
+		version: mx.version,
 	}
 
 	return im
@@ -265,8 +386,37 @@ func (mx *Mux) With(middlewares ...func(http.Handler) http.Handler) Router {
 // Group creates a new inline-Mux with a copy of middleware stack. It's useful
 // for a group of handlers along the same routing path that use an additional
 // set of middlewares. See _examples/.
+//
+// A group may define its own NotFound and MethodNotAllowed handlers, which
+// respond for unmatched paths under the longest prefix of whole path segments
+// shared by the routes registered through the group. For example, a group
+// with routes "/api/users" and "/api/orders/{id}" handles unmatched paths
+// under "/api", while other paths still get the router's handlers. A group
+// without any routes handles every unmatched path of the router.
 func (mx *Mux) Group(fn func(r Router)) Router {
-	im := mx.With()
+	im := mx.with()
+	if fn != nil {
+		fn(im)
+	}
+	return im
+}
+
+// Version creates a new inline-Mux registering its routes for the API
+// `version`. The same method and pattern may be registered for several
+// versions, and requests are served by the version resolved by the
+// Versioning middleware, see VersionOptions for the fallback rules. Routes
+// registered outside of Version serve every version, including the requests
+// none of the versions of the same method and pattern resolves to, whether
+// registered before or after them.
+//
+// Sub-routers may be mounted within Version, on the same pattern for several
+// versions. Note that Walk only goes through the last one mounted.
+func (mx *Mux) Version(version string, fn func(r Router)) Router {
+	if version == "" {
+		panic("chi: attempting to register routes for an empty Version()")
+	}
+	im := mx.with()
+	im.version = version
 	if fn != nil {
 		fn(im)
 	}
@@ -280,6 +430,9 @@ func (mx *Mux) Route(pattern string, fn func(r Router)) Router {
 		panic(fmt.Sprintf("chi: attempting to Route() a nil subrouter on '%s'", pattern))
 	}
 	subRouter := NewRouter()
+	subRouter.strict = mx.treeMux().strict
+	subRouter.RegisterMethod(mx.customMethods()...)
+	mx.inheritHooks(subRouter, pattern)
 	fn(subRouter)
 	mx.Mount(pattern, subRouter)
 	return subRouter
@@ -299,45 +452,64 @@ func (mx *Mux) Mount(pattern string, handler http.Handler) {
 
 	// Provide runtime safety for ensuring a pattern isn't mounted on an existing
 	// routing pattern.
-	if mx.tree.findPattern(pattern+"*") || mx.tree.findPattern(pattern+"/*") {
+	if (mx.tree.findPattern(pattern+"*") || mx.tree.findPattern(pattern+"/*")) && !mx.versionedMount(pattern) {
 		panic(fmt.Sprintf("chi: attempting to Mount() a handler on an existing path, '%s'", pattern))
 	}
 
-	// Assign sub-Router's with the parent not found & method not allowed handler if not specified.
-	subr, ok := handler.(*Mux)
-	if ok && subr.notFoundHandler == nil && mx.notFoundHandler != nil {
-		subr.NotFound(mx.notFoundHandler)
-	}
-	if ok && subr.methodNotAllowedHandler == nil && mx.methodNotAllowedHandler != nil {
-		subr.MethodNotAllowed(mx.methodNotAllowedHandler)
+	if subr, ok := handler.(*Mux); ok {
+		mx.inheritMount(subr, pattern)
 	}
 
 	mountHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		rctx := RouteContext(r.Context())
+		mx.enterMount(rctx, routePath(rctx, r))
+
+		handler := handler
+		final := false
+		if lm, ok := handler.(*lazyMount); ok {
+			// The routing took a MountFunc for a subrouter, but a handler
+			// other than a Routes is the final endpoint, like with Mount.
+			handler = lm.handler()
+			_, ok := handler.(Routes)
+			final = !ok
+		}
 
-		// shift the url path past the previous subrouter
-		rctx.RoutePath = mx.nextRoutePath(rctx)
-
-		// reset the wildcard URLParam which connects the subrouter
-		n := len(rctx.URLParams.Keys) - 1
-		if n >= 0 && rctx.URLParams.Keys[n] == "*" && len(rctx.URLParams.Values) > n {
-			rctx.URLParams.Values[n] = ""
+		// A handler other than a Mux won't run the pending UseMatched
+		// middlewares, so the mount is the final endpoint.
+		if _, ok := handler.(*Mux); !ok && len(rctx.matchedMiddlewares) > 0 {
+			handler = chain(rctx.matchedMiddlewares, handler)
+			rctx.matchedMiddlewares = rctx.matchedMiddlewares[:0]
 		}
 
+		if final {
+			handler = rctx.endpoint.mux.groupLimits(rctx.limits).handler(handler)
+			if hooks := mx.treeMux().hooks; hooks != nil && hooks.OnMatch != nil {
+				hooks.OnMatch(r)
+			}
+		}
 		handler.ServeHTTP(w, r)
 	})
 
-	if pattern == "" || pattern[len(pattern)-1] != '/' {
-		mx.handle(mALL|mSTUB, pattern, mountHandler)
-		mx.handle(mALL|mSTUB, pattern+"/", mountHandler)
-		pattern += "/"
-	}
-
 	method := mALL
 	subroutes, _ := handler.(Routes)
 	if subroutes != nil {
 		method |= mSTUB
 	}
+
+	if pattern == "" || pattern[len(pattern)-1] != '/' {
+		for _, p := range []string{pattern, pattern + "/"} {
+			n := mx.handle(mALL|mSTUB, p, mountHandler)
+			// The mount pattern stays hidden from Routes and the hooks, but
+			// a handler other than a Routes is the final endpoint for it.
+			if subroutes == nil {
+				for _, e := range n.endpoints {
+					e.stub = false
+				}
+			}
+		}
+		pattern += "/"
+	}
+
 	n := mx.handle(method, pattern+"*", mountHandler)
 
 	if subroutes != nil {
@@ -345,10 +517,29 @@ func (mx *Mux) Mount(pattern string, handler http.Handler) {
 	}
 }
 
+// inheritMount hands the handlers, custom methods and hooks of the router
+// down to the sub-router `subr` mounted on `pattern`, unless it has its own.
+func (mx *Mux) inheritMount(subr *Mux, pattern string) {
+	if subr.notFoundHandler == nil && mx.notFoundHandler != nil {
+		subr.NotFound(mx.notFoundHandler)
+	}
+	if subr.methodNotAllowedHandler == nil && mx.methodNotAllowedHandler != nil {
+		subr.MethodNotAllowed(mx.methodNotAllowedHandler)
+	}
+	if eh := mx.errorHandlerFunc(); subr.errorHandler == nil && eh != nil {
+		subr.ErrorHandler(eh)
+	}
+	if mx.treeMux().strict {
+		subr.Strict()
+	}
+	subr.RegisterMethod(mx.customMethods()...)
+	mx.mountHooks(subr, pattern)
+}
+
 // Routes returns a slice of routing information from the tree,
 // useful for traversing available routes of a router.
 func (mx *Mux) Routes() []Route {
-	return mx.tree.routes()
+	return mx.tree.routes(mx.methodName)
 }
 
 // Middlewares returns a slice of middleware handler functions.
@@ -372,9 +563,9 @@ func (mx *Mux) Match(rctx *Context, method, path string) bool {
 // Note: the *Context state is updated during execution, so manage
 // the state carefully or make a NewRouteContext().
 func (mx *Mux) Find(rctx *Context, method, path string) string {
-	m, ok := methodMap[method]
+	m, ok := mx.methodTyp(method)
 	if !ok {
-		return ""
+		m = mUNKNOWN
 	}
 
 	node, _, _ := mx.tree.FindRoute(rctx, m, path)
@@ -382,7 +573,7 @@ func (mx *Mux) Find(rctx *Context, method, path string) string {
 
 	if node != nil {
 		if node.subroutes == nil {
-			e := node.endpoints[m]
+			e := node.endpoints.find(m)
 			return e.pattern
 		}
 
@@ -399,6 +590,95 @@ func (mx *Mux) Find(rctx *Context, method, path string) string {
 	return pattern
 }
 
+// Handler returns the handler serving the request `r`, along with its
+// routing pattern and the middlewares wrapping the handler, without
+// executing any of them, like http.ServeMux#Handler.
+//
+// The request is routed through mounted subrouters, and the middlewares
+// are the ones a request runs through, in order: the Use middlewares of
+// the routers, along with the inline middlewares of With and Group, and
+// the UseMatched middlewares. For example, to check the scopes of a route
+// ahead of serving a request:
+//
+//	_, pattern, mws := r.Handler(req)
+//	if slices.Contains(mws.Names(), "auth") && !hasScope(token, pattern) {
+//		// reject the request
+//	}
+//
+// When no route matches the request, the handler is the NotFound, or
+// MethodNotAllowed handler, and the pattern is empty. Subrouters mounted
+// with MountFunc are built as needed. Subrouters mounted within Version
+// aren't searched, as the version serving the request is only resolved
+// while serving it: the handler is the one dispatching to the versions,
+// with the mount pattern.
+func (mx *Mux) Handler(r *http.Request) (h http.Handler, pattern string, mws Middlewares) {
+	rctx := NewRouteContext()
+	return mx.findHandler(rctx, r.Method, routePath(rctx, r), nil, nil)
+}
+
+// findHandler returns the handler of the route matching the method/path,
+// with its pattern and middlewares, `mws` and `matched` being the Use and
+// UseMatched middlewares of the parent routers.
+func (mx *Mux) findHandler(rctx *Context, method, path string, mws, matched Middlewares) (http.Handler, string, Middlewares) {
+	mws = slices.Concat(mws, mx.middlewares)
+	matched = slices.Concat(matched, mx.matchedMiddlewares)
+
+	m, ok := mx.methodTyp(method)
+	if !ok {
+		m = mUNKNOWN
+	}
+	node, _, h := mx.tree.FindRoute(rctx, m, path)
+	if node == nil {
+		if !ok {
+			return mx.methodNotAllowedFallback(rctx, path), "", mws
+		}
+		if rctx.methodNotAllowed {
+			return mx.methodNotAllowedFallback(rctx, path, rctx.methodsAllowed...), "", mws
+		}
+		return mx.notFoundFallback(rctx, path), "", mws
+	}
+	pattern := rctx.routePattern
+
+	// Continue at the subrouter of a mount, past its inline middlewares.
+	// The mount pattern without the trailing wildcard shares the subrouter
+	// of the wildcard one.
+	subroutes := node.subroutes
+	if rctx.endpoint.stub && subroutes == nil {
+		if n := mx.tree.findPatternNode(strings.TrimSuffix(pattern, "/") + "/*"); n != nil {
+			subroutes = n.subroutes
+		}
+	}
+	if _, ok := h.(*versionHandler); ok && rctx.endpoint.stub {
+		return h, pattern, slices.Concat(mws, matched)
+	}
+	if rctx.endpoint.stub && subroutes != nil {
+		if chain, ok := h.(*ChainHandler); ok {
+			mws = append(mws, chain.Middlewares...)
+		}
+		mx.enterMount(rctx, path)
+
+		sub, _ := subroutes.(http.Handler)
+		if lm, ok := sub.(*lazyMount); ok {
+			sub = lm.handler()
+		}
+		if subMux, ok := sub.(*Mux); ok {
+			h, subPattern, mws := subMux.findHandler(rctx, method, rctx.RoutePath, mws, matched)
+			if subPattern != "" {
+				subPattern = strings.TrimSuffix(strings.TrimSuffix(pattern, "/*"), "/") + subPattern
+			}
+			return h, subPattern, mws
+		}
+		return sub, pattern, slices.Concat(mws, matched)
+	}
+
+	mws = slices.Concat(mws, matched)
+	if chain, ok := h.(*ChainHandler); ok {
+		mws = append(mws, chain.Middlewares...)
+		h = chain.Endpoint
+	}
+	return h, pattern, mws
+}
+
 // NotFoundHandler returns the default Mux 404 responder whenever a route
 // cannot be found.
 func (mx *Mux) NotFoundHandler() http.HandlerFunc {
@@ -414,7 +694,7 @@ func (mx *Mux) MethodNotAllowedHandler(methodsAllowed ...methodTyp) http.Handler
 	if mx.methodNotAllowedHandler != nil {
 		return mx.methodNotAllowedHandler
 	}
-	return methodNotAllowedHandler(methodsAllowed...)
+	return methodNotAllowedHandler(mx, methodsAllowed...)
 }
 
 // handle registers a http.Handler in the routing tree for a particular http method
@@ -424,22 +704,61 @@ func (mx *Mux) handle(method methodTyp, pattern string, handler http.Handler) *n
 		panic(fmt.Sprintf("chi: routing pattern must begin with '/' in '%s'", pattern))
 	}
 
-	// Build the computed routing handler for this routing pattern.
-	if !mx.inline && mx.handler == nil {
-		mx.updateRouteHandler()
-	}
+	// The mux handler is built on the first request, now that there are
+	// routes to serve.
+	tm := mx.treeMux()
+	tm.hasRoutes = true
 
 	// Build endpoint handler with inline middlewares for the route
 	var h http.Handler
 	if mx.inline {
 		mx.handler = http.HandlerFunc(mx.routeHTTP)
 		h = Chain(mx.middlewares...).Handler(handler)
+
+		// Track the route prefix of the group and any enclosing groups
+		prefix := patStaticPrefix(pattern)
+		for m := mx; m != nil && m.inline; m = m.parent {
+			if !m.hasRoutes {
+				m.groupPrefix, m.hasRoutes = prefix, true
+			} else {
+				m.groupPrefix = commonSegmentPrefix(m.groupPrefix, prefix)
+			}
+		}
 	} else {
 		h = handler
 	}
 
+	// Serve the routes registered for a version through the handler
+	// dispatching to the version a request resolves to, which falls back to
+	// the route registered outside of Version, before or after them.
+	if mx.version != "" {
+		vh := tm.versionRoute(method, pattern)
+		if len(vh.versions) == 0 && method&mSTUB == 0 {
+			vh.fallback = tm.plainHandler(method, pattern)
+		}
+		h = vh.add(mx.version, h)
+	} else if vh := tm.versionRoutes[pattern][method]; vh != nil && method&mSTUB == 0 {
+		vh.fallback = h
+		h = vh
+	}
+
+	var site string
+	if tm.strict {
+		site = callerSite()
+		mx.checkRoute(method, pattern, h, site)
+		if tm.routedAt == "" {
+			tm.routedAt = fmt.Sprintf("the route '%s' registered at %s", pattern, site)
+		}
+	}
+
 	// Add the endpoint to the tree and return the node
-	return mx.tree.InsertRoute(method, pattern, h)
+	n := mx.tree.InsertRoute(method, pattern, h)
+	n.endpoints.each(method, func(e *endpoint) {
+		e.mux = mx
+		e.site = site
+	})
+	mx.registerHooks(method, pattern, handler)
+	return n
 }
 
 // routeHTTP routes a http.Request through the Mux routing tree to serve
@@ -449,26 +768,18 @@ func (mx *Mux) routeHTTP(w http.ResponseWriter, r *http.Request) {
 	rctx := r.Context().Value(RouteCtxKey).(*Context)
 
 	// The request routing path
-	routePath := rctx.RoutePath
-	if routePath == "" {
-		if r.URL.RawPath != "" {
-			routePath = r.URL.RawPath
-		} else {
-			routePath = r.URL.Path
-		}
-		if routePath == "" {
-			routePath = "/"
-		}
-	}
+	routePath := routePath(rctx, r)
+	rctx.routers = append(rctx.routers, mx)
 
 	// Check if method is supported by chi
 	if rctx.RouteMethod == "" {
 		rctx.RouteMethod = r.Method
 	}
-	method, ok := methodMap[rctx.RouteMethod]
+	hooks := mx.treeMux().hooks
+	method, ok := mx.methodTyp(rctx.RouteMethod)
 	if !ok {
-		mx.MethodNotAllowedHandler().ServeHTTP(w, r)
-		return
+		// A sub-router mounted on the router may know the method.
+		method = mUNKNOWN
 	}
 
 	// Find the route
@@ -480,13 +791,224 @@ func (mx *Mux) routeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 		r.Pattern = rctx.RoutePattern()
 
+		// Run the UseMatched middlewares once routing reaches the final
+		// endpoint, deferring them while it continues at a mounted handler.
+		if len(mx.matchedMiddlewares) > 0 || len(rctx.matchedMiddlewares) > 0 {
+			rctx.matchedMiddlewares = append(rctx.matchedMiddlewares, mx.matchedMiddlewares...)
+			if !rctx.endpoint.stub {
+				h = chain(rctx.matchedMiddlewares, h)
+				rctx.matchedMiddlewares = rctx.matchedMiddlewares[:0]
+			}
+		}
+
+		// Apply the limits of the routers traversed, and of the inline
+		// groups of the route, once routing reaches the final endpoint.
+		rctx.limits = rctx.limits.merge(mx.limits)
+		if !rctx.endpoint.stub {
+			h = rctx.endpoint.mux.groupLimits(rctx.limits).handler(h)
+		}
+
+		if hooks != nil && hooks.OnMatch != nil && !rctx.endpoint.stub {
+			hooks.OnMatch(r)
+		}
 		h.ServeHTTP(w, r)
 		return
 	}
-	if rctx.methodNotAllowed {
-		mx.MethodNotAllowedHandler(rctx.methodsAllowed...).ServeHTTP(w, r)
+	if !ok {
+		// The methods allowed on the path are unrelated to an unknown one.
+		rctx.methodsAllowed = rctx.methodsAllowed[:0]
+	}
+	if !ok || rctx.methodNotAllowed {
+		if hooks != nil && hooks.OnMethodNotAllowed != nil {
+			hooks.OnMethodNotAllowed(r)
+		}
+		// The default 405 responder doesn't escape from here, so serving a
+		// 405 doesn't allocate it.
+		h := mx.methodNotAllowedOverride(rctx, routePath)
+		if h == nil {
+			h = mx.MethodNotAllowedHandler(rctx.methodsAllowed...)
+		}
+		h.ServeHTTP(w, r)
 	} else {
-		mx.NotFoundHandler().ServeHTTP(w, r)
+		if hooks != nil && hooks.OnNotFound != nil {
+			hooks.OnNotFound(r)
+		}
+		mx.notFoundFallback(rctx, routePath).ServeHTTP(w, r)
+	}
+}
+
+// notFoundFallback returns the 404 responder for an unmatched `routePath`,
+// preferring the handler of the group the path falls under, then the mux's
+// own handler, and finally one handed down by a parent router.
+func (mx *Mux) notFoundFallback(rctx *Context, routePath string) http.HandlerFunc {
+	if h := mx.groupFallback(routePath, false); h != nil {
+		return h
+	}
+	if mx.notFoundHandler == nil && rctx.notFoundHandler != nil {
+		return rctx.notFoundHandler
+	}
+	return mx.NotFoundHandler()
+}
+
+// methodNotAllowedFallback returns the 405 responder for `routePath`, chosen
+// in the same order as notFoundFallback.
+func (mx *Mux) methodNotAllowedFallback(rctx *Context, routePath string, methodsAllowed ...methodTyp) http.HandlerFunc {
+	if h := mx.methodNotAllowedOverride(rctx, routePath); h != nil {
+		return h
+	}
+	return mx.MethodNotAllowedHandler(methodsAllowed...)
+}
+
+// methodNotAllowedOverride returns the 405 responder of the group
+// `routePath` falls under, or the one handed down by a parent router, or nil
+// for the router's own.
+func (mx *Mux) methodNotAllowedOverride(rctx *Context, routePath string) http.HandlerFunc {
+	if h := mx.groupFallback(routePath, true); h != nil {
+		return h
+	}
+	if mx.methodNotAllowedHandler == nil && rctx.methodNotAllowedHandler != nil {
+		return rctx.methodNotAllowedHandler
+	}
+	return nil
+}
+
+// groupFallback returns the NotFound, or MethodNotAllowed handler of the
+// group with the longest route prefix that `routePath` falls under. When two
+// groups share a prefix, the last registered one wins.
+func (mx *Mux) groupFallback(routePath string, methodNotAllowed bool) http.HandlerFunc {
+	var fallback http.HandlerFunc
+	longest := -1
+	for _, g := range mx.groups {
+		h := g.notFoundHandler
+		if methodNotAllowed {
+			h = g.methodNotAllowedHandler
+		}
+		if h == nil || len(g.groupPrefix) < longest {
+			continue
+		}
+		p := g.groupPrefix
+		if p == "" || routePath == p || strings.HasPrefix(routePath, p+"/") {
+			fallback, longest = h, len(p)
+		}
+	}
+	return fallback
+}
+
+// addGroup registers an inline group with its own fallback handlers on the
+// mux owning the routing tree.
+func (mx *Mux) addGroup(g *Mux) {
+	for _, x := range mx.groups {
+		if x == g {
+			return
+		}
+	}
+	mx.groups = append(mx.groups, g)
+}
+
+// errorHandlerFunc returns the error handler of the mux, or of the closest
+// enclosing mux of an inline group, if any.
+func (mx *Mux) errorHandlerFunc() ErrorHandlerFunc {
+	for m := mx; m != nil; m = m.parent {
+		if m.errorHandler != nil {
+			return m.errorHandler
+		}
+		if !m.inline {
+			break
+		}
+	}
+	return nil
+}
+
+// versionRoute returns the handler dispatching the versions of the route
+// with the `method` and `pattern`, registered on the mux owning the tree.
+func (mx *Mux) versionRoute(method methodTyp, pattern string) *versionHandler {
+	if mx.versionRoutes == nil {
+		mx.versionRoutes = map[string]map[methodTyp]*versionHandler{}
+	}
+	if mx.versionRoutes[pattern] == nil {
+		mx.versionRoutes[pattern] = map[methodTyp]*versionHandler{}
+	}
+	vh := mx.versionRoutes[pattern][method]
+	if vh == nil {
+		vh = &versionHandler{}
+		mx.versionRoutes[pattern][method] = vh
+	}
+	return vh
+}
+
+// plainHandler returns the handler registered for the `method` and
+// `pattern` outside of Version, or nil.
+func (mx *Mux) plainHandler(method methodTyp, pattern string) http.Handler {
+	n := mx.tree.findPatternNode(pattern)
+	if n == nil {
+		return nil
+	}
+	e := n.endpoints[method]
+	if e == nil || e.stub || e.pattern != pattern {
+		return nil
+	}
+	return e.handler
+}
+
+// versionedMount reports whether `pattern` is mounted for another version,
+// so a mux mounted on it for the version of this group adds to the
+// versions of the mount.
+func (mx *Mux) versionedMount(pattern string) bool {
+	if mx.version == "" {
+		return false
+	}
+	tm := mx.treeMux()
+	return tm.versionRoutes[pattern+"*"] != nil || tm.versionRoutes[pattern+"/*"] != nil
+}
+
+// treeMux returns the mux owning the routing tree shared by inline muxes.
+func (mx *Mux) treeMux() *Mux {
+	m := mx
+	for m.inline && m.parent != nil {
+		m = m.parent
+	}
+	return m
+}
+
+// routePath returns the path to route the request by, at the current
+// point in the routing lifecycle.
+func routePath(rctx *Context, r *http.Request) string {
+	routePath := rctx.RoutePath
+	if routePath == "" {
+		if r.URL.RawPath != "" {
+			routePath = r.URL.RawPath
+		} else {
+			routePath = r.URL.Path
+		}
+		if routePath == "" {
+			routePath = "/"
+		}
+	}
+	return routePath
+}
+
+// enterMount moves the routing context past the mount matching `routePath`,
+// on to the mounted subrouter.
+func (mx *Mux) enterMount(rctx *Context, routePath string) {
+	// Hand the NotFound and MethodNotAllowed handlers of the group the
+	// mount falls under down to the subrouter, which uses them unless it
+	// has handlers of its own.
+	if tm := mx.treeMux(); len(tm.groups) > 0 {
+		if h := tm.groupFallback(routePath, false); h != nil {
+			rctx.notFoundHandler = h
+		}
+		if h := tm.groupFallback(routePath, true); h != nil {
+			rctx.methodNotAllowedHandler = h
+		}
+	}
+
+	// shift the url path past the previous subrouter
+	rctx.RoutePath = mx.nextRoutePath(rctx)
+
+	// reset the wildcard URLParam which connects the subrouter
+	n := len(rctx.URLParams.Keys) - 1
+	if n >= 0 && rctx.URLParams.Keys[n] == "*" && len(rctx.URLParams.Values) > n {
+		rctx.URLParams.Values[n] = ""
 	}
 }
 
@@ -501,8 +1023,8 @@ func (mx *Mux) nextRoutePath(rctx *Context) string {
 
 // Recursively update data on child routers.
 func (mx *Mux) updateSubRoutes(fn func(subMux *Mux)) {
-	for _, r := range mx.tree.routes() {
-		subMux, ok := r.SubRoutes.(*Mux)
+	for _, r := range mx.tree.routes(mx.methodName) {
+		subMux, ok := mountedMux(r.SubRoutes)
 		if !ok {
 			continue
 		}
@@ -510,6 +1032,19 @@ func (mx *Mux) updateSubRoutes(fn func(subMux *Mux)) {
 	}
 }
 
+// routeHandler returns the mux handler, building it on the first request
+// once routes are defined, or nil if there are none yet.
+func (mx *Mux) routeHandler() http.Handler {
+	if mx.inline {
+		return mx.handler
+	}
+	if !mx.hasRoutes {
+		return nil
+	}
+	mx.buildOnce.Do(mx.updateRouteHandler)
+	return mx.handler
+}
+
 // updateRouteHandler builds the single mux handler that is a chain of the middleware
 // stack, as defined by calls to Use(), and the tree router (Mux) itself. After this
 // point, no other middlewares can be registered on this Mux's stack. But you can still
@@ -520,11 +1055,11 @@ func (mx *Mux) updateRouteHandler() {
 
 // methodNotAllowedHandler is a helper function to respond with a 405,
 // method not allowed. It sets the Allow header with the list of allowed
-// methods for the route.
-func methodNotAllowedHandler(methodsAllowed ...methodTyp) func(w http.ResponseWriter, r *http.Request) {
+// methods for the route, named after the methods of the router `mx`.
+func methodNotAllowedHandler(mx *Mux, methodsAllowed ...methodTyp) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		for _, m := range methodsAllowed {
-			w.Header().Add("Allow", reverseMethodMap[m])
+			w.Header().Add("Allow", mx.methodName(m))
 		}
 		w.WriteHeader(405)
 		w.Write(nil)
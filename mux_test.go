@@ -8,6 +8,8 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -498,6 +500,149 @@ func TestMuxNestedMethodNotAllowed(t *testing.T) {
 	}
 }
 
+func TestMuxGroupNotFound(t *testing.T) {
+	r := NewRouter()
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+		w.Write([]byte("<h1>not found</h1>"))
+	})
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("home"))
+	})
+
+	r.Group(func(r Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				next.ServeHTTP(w, r)
+			})
+		})
+		r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(404)
+			w.Write([]byte(`{"error":"not found"}`))
+		})
+		r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(405)
+			w.Write([]byte(`{"error":"method not allowed"}`))
+		})
+
+		r.Get("/api/users", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("users"))
+		})
+		r.Get("/api/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("user"))
+		})
+
+		r.Group(func(r Router) {
+			r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(404)
+				w.Write([]byte(`{"error":"no such admin resource"}`))
+			})
+			r.Get("/api/admin/stats", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("stats"))
+			})
+			r.Get("/api/admin/jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("job"))
+			})
+		})
+
+		sr := NewRouter()
+		sr.Get("/status", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("ok"))
+		})
+		r.Mount("/api/health", sr)
+	})
+
+	r.Get("/apiary", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("bees"))
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{"GET", "/", 200, "home"},
+		{"GET", "/api/users/1", 200, "user"},
+		{"GET", "/nope", 404, "<h1>not found</h1>"},
+		{"GET", "/apiary/nope", 404, "<h1>not found</h1>"},
+		{"GET", "/api", 404, `{"error":"not found"}`},
+		{"GET", "/api/nope", 404, `{"error":"not found"}`},
+		{"GET", "/api/users/1/nope", 404, `{"error":"not found"}`},
+		{"POST", "/api/users", 405, `{"error":"method not allowed"}`},
+		{"GET", "/api/admin/nope", 404, `{"error":"no such admin resource"}`},
+		{"GET", "/api/health/status", 200, "ok"},
+		{"GET", "/api/health/nope", 404, `{"error":"not found"}`},
+		{"PUT", "/api/health/status", 405, `{"error":"method not allowed"}`},
+	}
+	for _, tt := range tests {
+		resp, body := testRequest(t, ts, tt.method, tt.path, nil)
+		if resp.StatusCode != tt.status || body != tt.body {
+			t.Fatalf("%s %s: expected %d %q, got %d %q", tt.method, tt.path, tt.status, tt.body, resp.StatusCode, body)
+		}
+		if strings.HasPrefix(tt.body, "{") && resp.Header.Get("Content-Type") != "application/json" {
+			t.Fatalf("%s %s: expected group middlewares to run", tt.method, tt.path)
+		}
+	}
+}
+
+func TestMuxUseMatched(t *testing.T) {
+	var log []string
+	record := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				rctx := RouteContext(r.Context())
+				log = append(log, fmt.Sprintf("%s %s id=%s summary=%v", name, rctx.RoutePattern(), rctx.URLParam("id"), rctx.RouteMetadata()["summary"]))
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}
+
+	r := NewRouter()
+	r.Use(record("use"))
+	r.UseMatched(record("root"))
+	r.Method("GET", "/users/{id}", WithMetadata(http.HandlerFunc(ok), Metadata{"summary": "user"}))
+
+	sr := NewRouter()
+	sr.UseMatched(record("sub"))
+	sr.Get("/{id}", ok)
+	r.Mount("/teams", sr)
+
+	r.Mount("/static", http.HandlerFunc(ok))
+
+	r.Group(func(r Router) {
+		r.(*Mux).UseMatched(record("group"))
+		r.Get("/grouped/{id}", ok)
+	})
+
+	tests := []struct {
+		path string
+		log  []string
+	}{
+		{"/users/1", []string{"use  id= summary=<nil>", "root /users/{id} id=1 summary=user"}},
+		{"/teams/2", []string{"use  id= summary=<nil>", "root /teams/{id} id=2 summary=<nil>", "sub /teams/{id} id=2 summary=<nil>"}},
+		{"/static/app.js", []string{"use  id= summary=<nil>", "root /static/* id= summary=<nil>"}},
+		{"/static", []string{"use  id= summary=<nil>", "root /static id= summary=<nil>"}},
+		{"/grouped/3", []string{"use  id= summary=<nil>", "root /grouped/{id} id=3 summary=<nil>", "group /grouped/{id} id=3 summary=<nil>"}},
+		{"/nope", []string{"use  id= summary=<nil>"}},
+		{"/teams/2/nope", []string{"use  id= summary=<nil>"}},
+	}
+	for _, tt := range tests {
+		log = nil
+		testHandler(t, r, "GET", tt.path, nil)
+		if fmt.Sprint(log) != fmt.Sprint(tt.log) {
+			t.Fatalf("%s: expected %q, got %q", tt.path, tt.log, log)
+		}
+	}
+}
+
 func TestMuxComplicatedNotFound(t *testing.T) {
 	decorateRouter := func(r *Mux) {
 		// Root router with groups
@@ -1449,17 +1594,42 @@ func TestNestedGroups(t *testing.T) {
 	}
 }
 
-func TestMiddlewarePanicOnLateUse(t *testing.T) {
+func TestMiddlewareLateUse(t *testing.T) {
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("hello\n"))
 	}
 
 	mw := func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Late", "1")
 			next.ServeHTTP(w, r)
 		})
 	}
 
+	r := NewRouter()
+	r.Get("/", handler)
+	r.Group(func(r Router) {
+		r.Get("/group", handler)
+	})
+	r.Use(mw) // Applies to the routes registered above.
+
+	for _, path := range []string{"/", "/group", "/404"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Header().Get("X-Late") != "1" {
+			t.Errorf("expected the late middleware to serve %s", path)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic()")
+		}
+	}()
+	r.Use(mw) // Too late to apply middleware once serving, we're expecting panic().
+}
+
+func TestMiddlewarePanicOnLateInlineUse(t *testing.T) {
 	defer func() {
 		if recover() == nil {
 			t.Error("expected panic()")
@@ -1467,8 +1637,30 @@ func TestMiddlewarePanicOnLateUse(t *testing.T) {
 	}()
 
 	r := NewRouter()
-	r.Get("/", handler)
-	r.Use(mw) // Too late to apply middleware, we're expecting panic().
+	r.Group(func(r Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+		r.Use(func(next http.Handler) http.Handler { return next }) // Too late for the inline mux routes.
+	})
+}
+
+func TestMiddlewareLateUseConcurrentServe(t *testing.T) {
+	r := NewRouter()
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+	r.Use(func(next http.Handler) http.Handler { return next })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+			if w.Code != 200 {
+				t.Errorf("expected 200, got %d", w.Code)
+			}
+		}()
+	}
+	wg.Wait()
 }
 
 func TestMountingExistingPath(t *testing.T) {
@@ -2007,6 +2199,61 @@ func TestMuxFind(t *testing.T) {
 	}
 }
 
+func TestMuxHandler(t *testing.T) {
+	mw := func(next http.Handler) http.Handler { return next }
+	ok := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body))
+		}
+	}
+
+	admin := NewRouter()
+	admin.Use(Named("adminOnly", mw))
+	admin.With(Named("scope", mw)).Get("/users/{id}", ok("admin user"))
+
+	r := NewRouter()
+	r.Use(Named("logger", mw))
+	r.UseMatched(Named("metrics", mw))
+	r.Get("/", ok("index"))
+	r.Group(func(r Router) {
+		r.Use(Named("auth", mw))
+		r.Post("/items", ok("items"))
+	})
+	r.With(Named("admin", mw)).Mount("/admin", admin)
+	r.MountFunc("/lazy", func() http.Handler {
+		sr := NewRouter()
+		sr.Get("/", ok("lazy"))
+		return sr
+	})
+
+	tests := []struct {
+		method, path string
+		body         string
+		pattern      string
+		mws          []string
+	}{
+		{"GET", "/", "index", "/", []string{"logger", "metrics"}},
+		{"POST", "/items", "items", "/items", []string{"logger", "metrics", "auth"}},
+		{"GET", "/admin/users/1", "admin user", "/admin/users/{id}", []string{"logger", "admin", "adminOnly", "metrics", "scope"}},
+		{"GET", "/lazy/", "lazy", "/lazy/", []string{"logger", "metrics"}},
+		{"GET", "/lazy", "lazy", "/lazy/", []string{"logger", "metrics"}},
+		{"GET", "/admin/nope", "404 page not found\n", "", []string{"logger", "admin", "adminOnly"}},
+		{"PUT", "/", "", "", []string{"logger"}},
+	}
+	for _, tt := range tests {
+		h, pattern, mws := r.Handler(httptest.NewRequest(tt.method, tt.path, nil))
+		if pattern != tt.pattern || !slices.Equal(mws.Names(), tt.mws) {
+			t.Errorf("%s %s: expected %q %q, got %q %q", tt.method, tt.path, tt.pattern, tt.mws, pattern, mws.Names())
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Body.String() != tt.body {
+			t.Errorf("%s %s: expected body %q, got %q", tt.method, tt.path, tt.body, w.Body.String())
+		}
+	}
+}
+
 func TestServerBaseContext(t *testing.T) {
 	r := NewRouter()
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,77 @@
+package openapi
+
+import (
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Handler returns a http.Handler serving the OpenAPI document of the routes
+// of `r`, for example:
+//
+//	spec := openapi.Handler(r, cfg)
+//	r.Method("GET", "/openapi.json", spec)
+//	r.Method("GET", "/openapi.yaml", spec)
+//
+// The document is generated on the first request, after all routes have
+// been registered. It is served as YAML when the request path ends in
+// ".yaml" or ".yml", or the Accept header asks for YAML, and as JSON
+// otherwise.
+//
+// The routes serving the document are left out of it, as long as the
+// handler is registered with Handle or Method, which keep its metadata.
+func Handler(r chi.Routes, cfg Config) http.Handler {
+	return &specHandler{routes: r, cfg: cfg}
+}
+
+type specHandler struct {
+	routes chi.Routes
+	cfg    Config
+
+	once sync.Once
+	json []byte
+	yaml []byte
+	err  error
+}
+
+func (h *specHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.once.Do(func() {
+		var doc *Document
+		if doc, h.err = Generate(h.routes, h.cfg); h.err != nil {
+			return
+		}
+		if h.json, h.err = doc.JSON(); h.err != nil {
+			return
+		}
+		h.yaml, h.err = doc.YAML()
+	})
+	if h.err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if wantsYAML(r) {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(h.yaml)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(h.json)
+}
+
+func (h *specHandler) Metadata() chi.Metadata {
+	return chi.Metadata{MetaExclude: true}
+}
+
+func wantsYAML(r *http.Request) bool {
+	switch path.Ext(r.URL.Path) {
+	case ".yaml", ".yml":
+		return true
+	case ".json":
+		return false
+	}
+	return strings.Contains(r.Header.Get("Accept"), "yaml")
+}
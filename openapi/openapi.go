@@ -0,0 +1,239 @@
+// Package openapi generates OpenAPI 3.1 documents from chi routers.
+//
+// The document is built by walking the routes of a router, including its
+// mounted sub-routers. Every route becomes an operation, with the path
+// params of the chi pattern described as OpenAPI path params, and their
+// regexp constraints as the `pattern` of the param schema.
+//
+// Operations are enriched from the chi.Metadata of the route handlers: the
+// request and response types of a chi.JSON endpoint become the request
+// body, query params and response schemas, and the Meta* keys of this
+// package set the summary, tags, security and more. For example:
+//
+//	r.Method("GET", "/users/{id:[0-9]+}", chi.WithMetadata(chi.JSON(getUser), chi.Metadata{
+//		openapi.MetaSummary: "Get a user",
+//		openapi.MetaTags:    []string{"users"},
+//	}))
+//
+//	r.Method("GET", "/openapi.json", openapi.Handler(r, openapi.Config{
+//		Info: openapi.Info{Title: "Users API", Version: "1.0.0"},
+//	}))
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Version is the OpenAPI specification version of the generated documents.
+const Version = "3.1.0"
+
+// Route metadata keys understood by Generate. See chi.WithMetadata.
+const (
+	// MetaSummary is the string summary of the operation.
+	MetaSummary = "openapi.summary"
+
+	// MetaDescription is the string description of the operation.
+	MetaDescription = "openapi.description"
+
+	// MetaOperationID is the string unique identifier of the operation.
+	MetaOperationID = "openapi.operationId"
+
+	// MetaTags is the []string list of tags of the operation.
+	MetaTags = "openapi.tags"
+
+	// MetaSecurity is the []SecurityRequirement list of the operation,
+	// overriding the document's Security. An empty list removes security
+	// from the operation.
+	MetaSecurity = "openapi.security"
+
+	// MetaDeprecated is a bool marking the operation as deprecated.
+	MetaDeprecated = "openapi.deprecated"
+
+	// MetaExclude is a bool excluding the route from the document.
+	MetaExclude = "openapi.exclude"
+)
+
+// Config is the document-level configuration of Generate.
+type Config struct {
+	// Info is the metadata of the API.
+	Info Info
+
+	// Servers lists the servers providing the API.
+	Servers []Server
+
+	// SecuritySchemes are the security schemes the operations can use,
+	// keyed by name.
+	SecuritySchemes map[string]*SecurityScheme
+
+	// Security lists the security requirements applied to all operations,
+	// unless overridden by the MetaSecurity of a route.
+	Security []SecurityRequirement
+}
+
+// Generate returns the OpenAPI document describing the routes of `r`.
+func Generate(r chi.Routes, cfg Config) (*Document, error) {
+	doc := &Document{
+		OpenAPI:  Version,
+		Info:     cfg.Info,
+		Servers:  cfg.Servers,
+		Paths:    map[string]*PathItem{},
+		Security: cfg.Security,
+	}
+	gen := newSchemaGenerator()
+
+	err := chi.Walk(r, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		md := chi.HandlerMetadata(handler)
+		if exclude, _ := md[MetaExclude].(bool); exclude {
+			return nil
+		}
+
+		path, params, err := pathTemplate(route)
+		if err != nil {
+			return err
+		}
+		item := doc.Paths[path]
+		if item == nil {
+			item = &PathItem{}
+		}
+		op := item.operation(method)
+		if op == nil {
+			// Not representable in OpenAPI, like CONNECT or custom methods.
+			return nil
+		}
+		*op = newOperation(gen, md, params)
+		doc.Paths[path] = item
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(gen.schemas) > 0 || len(cfg.SecuritySchemes) > 0 {
+		doc.Components = &Components{
+			Schemas:         gen.schemas,
+			SecuritySchemes: cfg.SecuritySchemes,
+		}
+	}
+	return doc, nil
+}
+
+// newOperation builds the operation of a route from its handler metadata
+// and path params.
+func newOperation(gen *schemaGenerator, md chi.Metadata, params []chi.Segment) *Operation {
+	op := &Operation{Responses: map[string]*Response{}}
+	op.Summary, _ = md[MetaSummary].(string)
+	op.Description, _ = md[MetaDescription].(string)
+	op.OperationID, _ = md[MetaOperationID].(string)
+	op.Tags, _ = md[MetaTags].([]string)
+	op.Deprecated, _ = md[MetaDeprecated].(bool)
+	if security, ok := md[MetaSecurity].([]SecurityRequirement); ok {
+		if security == nil {
+			security = []SecurityRequirement{}
+		}
+		op.Security = &security
+	}
+
+	reqType, _ := md[chi.MetaRequestType].(reflect.Type)
+	bound := boundFields(reqType)
+
+	for _, p := range params {
+		schema := &Schema{Type: "string"}
+		if f, ok := bound["param:"+p.Name]; ok {
+			schema = gen.schema(f.Type)
+		}
+		if p.Regexp != "" {
+			schema.Pattern = anchorRegexp(p.Regexp)
+		}
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:     p.Name,
+			In:       "path",
+			Required: true,
+			Schema:   schema,
+		})
+	}
+
+	if reqType != nil {
+		if st := derefType(reqType); st.Kind() == reflect.Struct {
+			for _, f := range reflect.VisibleFields(st) {
+				if name, ok := f.Tag.Lookup("query"); ok && f.IsExported() {
+					op.Parameters = append(op.Parameters, &Parameter{
+						Name:   name,
+						In:     "query",
+						Schema: gen.schema(f.Type),
+					})
+				}
+			}
+		}
+		if body := gen.requestSchema(reqType); body != nil {
+			op.RequestBody = &RequestBody{
+				Content: map[string]*MediaType{"application/json": {Schema: body}},
+			}
+		}
+	}
+
+	if respType, ok := md[chi.MetaResponseType].(reflect.Type); ok {
+		op.Responses["200"] = &Response{
+			Description: http.StatusText(http.StatusOK),
+			Content:     map[string]*MediaType{"application/json": {Schema: gen.schema(respType)}},
+		}
+	} else {
+		op.Responses["default"] = &Response{Description: "Default response"}
+	}
+
+	return op
+}
+
+// pathTemplate converts a chi route pattern into an OpenAPI path template,
+// returning its params in order. A trailing catch-all `*` becomes the `{*}`
+// param, as it is named by chi.URLParam.
+func pathTemplate(pattern string) (string, []chi.Segment, error) {
+	p, err := chi.ParsePattern(pattern)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var (
+		b      strings.Builder
+		params []chi.Segment
+	)
+	for _, seg := range p.Segments {
+		if seg.Type == chi.SegmentStatic {
+			b.WriteString(seg.Text)
+			continue
+		}
+		params = append(params, seg)
+		b.WriteString("{" + seg.Name + "}")
+	}
+	return b.String(), params, nil
+}
+
+// anchorRegexp anchors a param regexp the same way chi matches it, to the
+// whole segment.
+func anchorRegexp(rexp string) string {
+	if rexp[0] != '^' {
+		rexp = "^" + rexp
+	}
+	if rexp[len(rexp)-1] != '$' {
+		rexp += "$"
+	}
+	return rexp
+}
+
+// JSON returns the document encoded as indented JSON.
+func (d *Document) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// YAML returns the document encoded as YAML.
+func (d *Document) YAML() ([]byte, error) {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
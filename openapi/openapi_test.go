@@ -0,0 +1,274 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type testUser struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Email     *string   `json:"email"`
+	Tags      []string  `json:"tags,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	Manager   *testUser `json:"manager,omitempty"`
+	internal  string
+}
+
+type testGetUser struct {
+	ID     int64    `param:"id"`
+	Fields []string `query:"fields"`
+}
+
+type testCreateUser struct {
+	OrgID string `param:"org"`
+	Name  string `json:"name"`
+}
+
+func testRouter() chi.Router {
+	getUser := func(ctx context.Context, req testGetUser) (*testUser, error) { return nil, nil }
+	createUser := func(ctx context.Context, req testCreateUser) (testUser, error) { return testUser{}, nil }
+
+	r := chi.NewRouter()
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+
+	r.Route("/orgs/{org}", func(r chi.Router) {
+		r.Method("GET", "/users/{id:[0-9]+}", chi.WithMetadata(chi.JSON(getUser), chi.Metadata{
+			MetaSummary:     "Get a user",
+			MetaOperationID: "getUser",
+			MetaTags:        []string{"users"},
+		}))
+		r.Method("POST", "/users", chi.WithMetadata(chi.JSON(createUser), chi.Metadata{
+			MetaSecurity: []SecurityRequirement{{"bearer": {"users:write"}}},
+		}))
+	})
+
+	sub := chi.NewRouter()
+	sub.Delete("/{code:[a-z]{2}}", func(w http.ResponseWriter, r *http.Request) {})
+	sub.Method("GET", "/internal", chi.WithMetadata(http.NotFoundHandler(), chi.Metadata{MetaExclude: true}))
+	r.Mount("/langs", sub)
+
+	return r
+}
+
+func TestGenerate(t *testing.T) {
+	doc, err := Generate(testRouter(), Config{
+		Info: Info{Title: "Test API", Version: "1.0.0"},
+		SecuritySchemes: map[string]*SecurityScheme{
+			"bearer": {Type: "http", Scheme: "bearer"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.OpenAPI != "3.1.0" || doc.Info.Title != "Test API" {
+		t.Fatalf("unexpected document header: %+v", doc)
+	}
+
+	var paths []string
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	wantPaths := []string{"/", "/langs/{code}", "/orgs/{org}/users", "/orgs/{org}/users/{id}"}
+	if len(paths) != len(wantPaths) {
+		t.Fatalf("expected paths %v, got %v", wantPaths, paths)
+	}
+	for _, path := range wantPaths {
+		if doc.Paths[path] == nil {
+			t.Fatalf("expected path %s, got %v", path, paths)
+		}
+	}
+
+	if op := doc.Paths["/"].Get; op == nil || op.Responses["default"] == nil {
+		t.Fatalf("expected a GET / operation with a default response, got %+v", op)
+	}
+
+	del := doc.Paths["/langs/{code}"].Delete
+	if del == nil || len(del.Parameters) != 1 || del.Parameters[0].Schema.Pattern != "^[a-z]{2}$" {
+		t.Fatalf("expected the DELETE /langs/{code} param to have a pattern, got %+v", del)
+	}
+
+	get := doc.Paths["/orgs/{org}/users/{id}"].Get
+	if get.Summary != "Get a user" || get.OperationID != "getUser" || !reflect.DeepEqual(get.Tags, []string{"users"}) {
+		t.Fatalf("expected the operation metadata to be set, got %+v", get)
+	}
+	if len(get.Parameters) != 3 {
+		t.Fatalf("expected 3 params, got %d", len(get.Parameters))
+	}
+	if p := get.Parameters[1]; p.Name != "id" || p.In != "path" || !p.Required || p.Schema.Type != "integer" || p.Schema.Pattern != "^[0-9]+$" {
+		t.Fatalf("unexpected id param: %+v %+v", p, p.Schema)
+	}
+	if p := get.Parameters[2]; p.Name != "fields" || p.In != "query" || p.Schema.Type != "array" {
+		t.Fatalf("unexpected fields param: %+v", p)
+	}
+	if get.RequestBody != nil {
+		t.Fatalf("expected no request body for a request fully bound from params, got %+v", get.RequestBody)
+	}
+	if ref := get.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/testUser" {
+		t.Fatalf("expected the response to reference the testUser schema, got %q", ref)
+	}
+
+	post := doc.Paths["/orgs/{org}/users"].Post
+	body := post.RequestBody.Content["application/json"].Schema
+	if _, ok := body.Properties["OrgID"]; ok || body.Properties["name"] == nil {
+		t.Fatalf("expected the body schema to only have the name property, got %+v", body.Properties)
+	}
+	if post.Security == nil || (*post.Security)[0]["bearer"][0] != "users:write" {
+		t.Fatalf("expected the operation security to be set, got %+v", post.Security)
+	}
+
+	user := doc.Components.Schemas["testUser"]
+	if user == nil {
+		t.Fatal("expected the testUser schema in the components")
+	}
+	if !reflect.DeepEqual(user.Required, []string{"id", "name", "createdAt"}) {
+		t.Fatalf("unexpected required properties: %v", user.Required)
+	}
+	if user.Properties["createdAt"].Format != "date-time" || user.Properties["manager"].Ref != "#/components/schemas/testUser" {
+		t.Fatalf("unexpected properties: %+v", user.Properties)
+	}
+	if _, ok := user.Properties["internal"]; ok {
+		t.Fatal("expected unexported fields to be left out")
+	}
+	if doc.Components.SecuritySchemes["bearer"] == nil {
+		t.Fatal("expected the security schemes in the components")
+	}
+}
+
+func TestPathTemplate(t *testing.T) {
+	param := func(name string) chi.Segment { return chi.Segment{Type: chi.SegmentParam, Name: name} }
+	rexp := func(name, rexp string) chi.Segment {
+		return chi.Segment{Type: chi.SegmentRegexp, Name: name, Regexp: rexp}
+	}
+
+	tests := []struct {
+		pattern string
+		path    string
+		params  []chi.Segment
+	}{
+		{"/", "/", nil},
+		{"/users/{id}", "/users/{id}", []chi.Segment{param("id")}},
+		{"/users/{id:[0-9]+}/posts/{slug}", "/users/{id}/posts/{slug}", []chi.Segment{rexp("id", "[0-9]+"), param("slug")}},
+		{"/langs/{code:[a-z]{2}}", "/langs/{code}", []chi.Segment{rexp("code", "[a-z]{2}")}},
+		{"/static/*", "/static/{*}", []chi.Segment{{Type: chi.SegmentCatchAll, Name: "*"}}},
+		{"/{a}-{b}.json", "/{a}-{b}.json", []chi.Segment{param("a"), param("b")}},
+	}
+	for _, tt := range tests {
+		path, params, err := pathTemplate(tt.pattern)
+		if err != nil || path != tt.path || !reflect.DeepEqual(params, tt.params) {
+			t.Errorf("pathTemplate(%q) = %q, %v, %v; expected %q, %v", tt.pattern, path, params, err, tt.path, tt.params)
+		}
+	}
+}
+
+func TestComponentName(t *testing.T) {
+	type page[T any] struct{ Items []T }
+
+	if name := componentName(reflect.TypeFor[testUser]()); name != "testUser" {
+		t.Fatalf("expected testUser, got %q", name)
+	}
+	if name := componentName(reflect.TypeFor[page[testUser]]()); name != "page_testUser" {
+		t.Fatalf("expected page_testUser, got %q", name)
+	}
+}
+
+func TestDocumentYAML(t *testing.T) {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: "Test: API", Version: "1.0"},
+		Paths: map[string]*PathItem{
+			"/users/{id}": {Get: &Operation{
+				Tags: []string{"users", "yes"},
+				Parameters: []*Parameter{
+					{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}},
+				},
+				Responses: map[string]*Response{"default": {Description: "Default response"}},
+			}},
+		},
+	}
+
+	b, err := doc.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `openapi: "3.1.0"
+info:
+  title: "Test: API"
+  version: "1.0"
+paths:
+  "/users/{id}":
+    get:
+      tags:
+        - users
+        - "yes"
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        default:
+          description: "Default response"
+`
+	if string(b) != want {
+		t.Fatalf("unexpected YAML:\n%s\nexpected:\n%s", b, want)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	r := testRouter()
+	spec := Handler(r, Config{Info: Info{Title: "Test API", Version: "1.0.0"}})
+	r.Method("GET", "/openapi.json", spec)
+	r.Method("GET", "/openapi.yaml", spec)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected a JSON document, got %q", ct)
+	}
+	var doc Document
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Paths["/orgs/{org}/users/{id}"] == nil {
+		t.Fatalf("expected the document to describe the routes, got %v", doc.Paths)
+	}
+	if doc.Paths["/openapi.json"] != nil {
+		t.Fatal("expected the document routes to be left out")
+	}
+
+	resp, err = http.Get(ts.URL + "/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "application/yaml" {
+		t.Fatalf("expected a YAML document, got %q", ct)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), `openapi: "3.1.0"`) {
+		t.Fatalf("unexpected YAML document:\n%s", b)
+	}
+}
@@ -0,0 +1,224 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// schemaGenerator builds the schemas of Go types, following the rules of
+// encoding/json. Named struct types are added to the document components
+// and referenced.
+type schemaGenerator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		schemas: map[string]*Schema{},
+		names:   map[reflect.Type]string{},
+	}
+}
+
+// schema returns the schema of values of type t.
+func (g *schemaGenerator) schema(t reflect.Type) *Schema {
+	t = derefType(t)
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case implements(t, jsonMarshalerType):
+		// Custom JSON encoding, anything goes.
+		return &Schema{}
+	case implements(t, textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t, nil)
+		}
+		return &Schema{Ref: "#/components/schemas/" + g.component(t)}
+	}
+
+	// Interfaces, and values encoding/json can't encode.
+	return &Schema{}
+}
+
+// requestSchema returns the schema of the JSON request body decoded into
+// type t, or nil if no body is expected. The fields bound from the URL
+// params or query string are left out.
+func (g *schemaGenerator) requestSchema(t reflect.Type) *Schema {
+	st := derefType(t)
+	if st.Kind() != reflect.Struct {
+		return g.schema(t)
+	}
+	if len(boundFields(st)) == 0 {
+		if st.NumField() == 0 {
+			return nil
+		}
+		return g.schema(t)
+	}
+
+	schema := g.structSchema(st, func(f reflect.StructField) bool {
+		_, param := f.Tag.Lookup("param")
+		_, query := f.Tag.Lookup("query")
+		return param || query
+	})
+	if len(schema.Properties) == 0 {
+		return nil
+	}
+	return schema
+}
+
+// component registers the schema of the named struct type t in the
+// document components, and returns its name.
+func (g *schemaGenerator) component(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	name := componentName(t)
+	for i := 2; g.schemas[name] != nil; i++ {
+		// Same type name in another package.
+		name = componentName(t) + strconv.Itoa(i)
+	}
+
+	// Register the name ahead of building the schema, for recursive types.
+	g.names[t] = name
+	g.schemas[name] = &Schema{}
+	*g.schemas[name] = *g.structSchema(t, nil)
+	return name
+}
+
+var (
+	pkgPathRe       = regexp.MustCompile(`(?:[\w-]+[./])+`)
+	componentNameRe = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+)
+
+// componentName returns the name of a named type in the document
+// components. Type arguments of generic types are kept without their
+// package, with the characters components can't use replaced, as in
+// Page_User for Page[example.com/api.User].
+func componentName(t reflect.Type) string {
+	name := pkgPathRe.ReplaceAllString(t.Name(), "")
+	return strings.Trim(componentNameRe.ReplaceAllString(name, "_"), "_")
+}
+
+// structSchema returns the object schema of the struct type t, leaving out
+// the fields for which skip returns true.
+func (g *schemaGenerator) structSchema(t reflect.Type, skip func(reflect.StructField) bool) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.addFields(schema, t, skip)
+	return schema
+}
+
+// addFields adds the encoded fields of the struct type t to the object
+// schema, including the promoted fields of embedded structs.
+func (g *schemaGenerator) addFields(schema *Schema, t reflect.Type, skip func(reflect.StructField) bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || (skip != nil && skip(f)) {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			if ft := derefType(f.Type); ft.Kind() == reflect.Struct {
+				g.addFields(schema, ft, skip)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		schema.Properties[name] = g.schema(f.Type)
+		if f.Type.Kind() != reflect.Pointer && !hasOption(opts, "omitempty") && !hasOption(opts, "omitzero") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// boundFields returns the fields of the struct type t bound from the URL
+// params or the query string by chi.JSON, keyed by "param:name" or
+// "query:name".
+func boundFields(t reflect.Type) map[string]reflect.StructField {
+	if t == nil {
+		return nil
+	}
+	t = derefType(t)
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := map[string]reflect.StructField{}
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() {
+			continue
+		}
+		if name, ok := f.Tag.Lookup("param"); ok {
+			fields["param:"+name] = f
+		} else if name, ok := f.Tag.Lookup("query"); ok {
+			fields["query:"+name] = f
+		}
+	}
+	return fields
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+func hasOption(opts, name string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// yamlNode is a JSON value, with the order of object keys kept.
+type yamlNode struct {
+	keys   []string    // object keys
+	values []*yamlNode // object or array values
+	object bool
+	array  bool
+	scalar string // encoded scalar value
+}
+
+// jsonToYAML converts a JSON document into the equivalent YAML document,
+// in block style and with the order of object keys kept.
+func jsonToYAML(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	root, err := decodeYAMLNode(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("openapi: unexpected data after the JSON document")
+	}
+
+	var buf bytes.Buffer
+	switch {
+	case root.object && len(root.keys) > 0:
+		writeYAMLObject(&buf, root, 0, false)
+	case root.array && len(root.values) > 0:
+		writeYAMLArray(&buf, root, 0)
+	default:
+		buf.WriteString(yamlInline(root) + "\n")
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeYAMLNode(dec *json.Decoder) (*yamlNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		n := &yamlNode{object: v == '{', array: v == '['}
+		for dec.More() {
+			if n.object {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				n.keys = append(n.keys, key.(string))
+			}
+			value, err := decodeYAMLNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.values = append(n.values, value)
+		}
+		// Closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return n, nil
+
+	case string:
+		return &yamlNode{scalar: yamlString(v)}, nil
+	case json.Number:
+		return &yamlNode{scalar: v.String()}, nil
+	case bool:
+		return &yamlNode{scalar: fmt.Sprint(v)}, nil
+	case nil:
+		return &yamlNode{scalar: "null"}, nil
+	}
+	return nil, fmt.Errorf("openapi: unexpected JSON token %v", tok)
+}
+
+// writeYAMLObject writes the object as a block mapping, with the first key
+// on the current line if inline is set, as in an array item.
+func writeYAMLObject(buf *bytes.Buffer, n *yamlNode, indent int, inline bool) {
+	for i, key := range n.keys {
+		if i > 0 || !inline {
+			buf.WriteString(strings.Repeat(" ", indent))
+		}
+		buf.WriteString(yamlString(key) + ":")
+		writeYAMLValue(buf, n.values[i], indent+2)
+	}
+}
+
+// writeYAMLArray writes the array as a block sequence.
+func writeYAMLArray(buf *bytes.Buffer, n *yamlNode, indent int) {
+	for _, value := range n.values {
+		buf.WriteString(strings.Repeat(" ", indent) + "-")
+		if value.object && len(value.keys) > 0 {
+			buf.WriteString(" ")
+			writeYAMLObject(buf, value, indent+2, true)
+			continue
+		}
+		writeYAMLValue(buf, value, indent+2)
+	}
+}
+
+// writeYAMLValue writes the value following a mapping key or sequence
+// indicator, on the same line if it is a scalar or empty collection.
+func writeYAMLValue(buf *bytes.Buffer, n *yamlNode, indent int) {
+	switch {
+	case n.object && len(n.keys) > 0:
+		buf.WriteString("\n")
+		writeYAMLObject(buf, n, indent, false)
+	case n.array && len(n.values) > 0:
+		buf.WriteString("\n")
+		writeYAMLArray(buf, n, indent)
+	default:
+		buf.WriteString(" " + yamlInline(n) + "\n")
+	}
+}
+
+// yamlInline returns the flow representation of a scalar or an empty
+// collection.
+func yamlInline(n *yamlNode) string {
+	switch {
+	case n.object:
+		return "{}"
+	case n.array:
+		return "[]"
+	}
+	return n.scalar
+}
+
+// yamlPlainRe matches the strings that can be written as plain scalars,
+// without being read back as another type.
+var yamlPlainRe = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_.$/-]*$`)
+
+// yamlString returns the YAML representation of a string, as a plain scalar
+// when possible, or a double-quoted one otherwise. JSON strings are valid
+// double-quoted YAML scalars.
+func yamlString(s string) string {
+	if yamlPlainRe.MatchString(s) {
+		switch strings.ToLower(s) {
+		case "y", "yes", "n", "no", "true", "false", "on", "off", "null":
+		default:
+			return s
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,236 @@
+package chi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// SegmentType is the type of a Segment of a routing pattern.
+type SegmentType int
+
+const (
+	// SegmentStatic is static text, as in "/users/".
+	SegmentStatic SegmentType = iota
+
+	// SegmentParam is a param, as in "{id}".
+	SegmentParam
+
+	// SegmentRegexp is a param with a regexp, as in "{id:[0-9]+}".
+	SegmentRegexp
+
+	// SegmentCatchAll is the catch-all param "*" ending a pattern.
+	SegmentCatchAll
+)
+
+// Segment is a part of a routing pattern: either static text, or a param.
+type Segment struct {
+	Type SegmentType
+
+	// Text is the text of a SegmentStatic segment.
+	Text string
+
+	// Name is the name of a param, or "*" for the catch-all param.
+	Name string
+
+	// Regexp is the regexp of a SegmentRegexp param, as written in the
+	// pattern. The router matches it against the whole param value, as if
+	// anchored with ^ and $.
+	Regexp string
+}
+
+// Pattern is a routing pattern parsed the way the router parses it, see
+// ParsePattern.
+type Pattern struct {
+	// Segments are the parts of the pattern, in order.
+	Segments []Segment
+
+	// tree routes the pattern, for Match
+	tree *node
+}
+
+// ParsePattern parses a routing pattern, as registered on a router, for
+// example:
+//
+//	p, err := chi.ParsePattern("/users/{id:[0-9]+}/files/*")
+//	p.ParamNames()                                   // ["id" "*"]
+//	p.Normalize()                                    // "/users/{:[0-9]+}/files/*"
+//	p.Match("/users/1/files/a/b.txt")                // id=1, *=a/b.txt
+//	p.Expand(map[string]string{"id": "2", "*": "c"}) // "/users/2/files/c"
+//
+// It returns an error for a pattern the router panics on, such as a pattern
+// not beginning with '/', or with an unterminated param, an invalid regexp,
+// a duplicate param name, or text after the catch-all param.
+func ParsePattern(pattern string) (Pattern, error) {
+	if len(pattern) == 0 || pattern[0] != '/' {
+		return Pattern{}, fmt.Errorf("chi: routing pattern must begin with '/' in '%s'", pattern)
+	}
+
+	var p Pattern
+	seen := map[string]bool{}
+
+	for pat := pattern; pat != ""; {
+		ptyp, key, rexpat, _, ps, pe, err := patParseSegment(pat)
+		if err != nil {
+			return Pattern{}, fmt.Errorf("%w, in '%s'", err, pattern)
+		}
+		if ptyp == ntStatic {
+			p.Segments = append(p.Segments, Segment{Type: SegmentStatic, Text: pat})
+			break
+		}
+		if ps > 0 {
+			p.Segments = append(p.Segments, Segment{Type: SegmentStatic, Text: pat[:ps]})
+		}
+
+		seg := Segment{Type: SegmentParam, Name: key}
+		switch ptyp {
+		case ntRegexp:
+			if _, err := regexp.Compile(rexpat); err != nil {
+				return Pattern{}, fmt.Errorf("chi: invalid regexp pattern '%s' in route param, in '%s'", rexpat, pattern)
+			}
+			seg.Type = SegmentRegexp
+			_, seg.Regexp, _ = strings.Cut(pat[ps+1:pe-1], ":")
+		case ntCatchAll:
+			seg.Type = SegmentCatchAll
+		}
+		if seen[key] {
+			return Pattern{}, fmt.Errorf("chi: routing pattern '%s' contains duplicate param key, '%s'", pattern, key)
+		}
+		seen[key] = true
+
+		p.Segments = append(p.Segments, seg)
+		pat = pat[pe:]
+	}
+
+	p.tree = &node{}
+	p.tree.InsertRoute(mGET, pattern, http.NotFoundHandler())
+	return p, nil
+}
+
+// ParamNames returns the names of the params of the pattern, in order.
+func (p Pattern) ParamNames() []string {
+	names := []string{}
+	for _, seg := range p.Segments {
+		if seg.Type != SegmentStatic {
+			names = append(names, seg.Name)
+		}
+	}
+	return names
+}
+
+// CatchAll returns the index of the catch-all param in the segments of the
+// pattern, or -1 if the pattern has none.
+func (p Pattern) CatchAll() int {
+	if n := len(p.Segments); n > 0 && p.Segments[n-1].Type == SegmentCatchAll {
+		return n - 1
+	}
+	return -1
+}
+
+// Format returns the pattern in the routing pattern syntax.
+func (p Pattern) Format() string {
+	return p.format(true)
+}
+
+// String returns the pattern in the routing pattern syntax.
+func (p Pattern) String() string {
+	return p.Format()
+}
+
+// Normalize returns the pattern without the names of its params, as in
+// "/users/{}/posts/{:[0-9]+}/*", so patterns matching the same paths
+// normalize to the same string.
+func (p Pattern) Normalize() string {
+	return p.format(false)
+}
+
+func (p Pattern) format(names bool) string {
+	var b strings.Builder
+	for _, seg := range p.Segments {
+		switch seg.Type {
+		case SegmentStatic:
+			b.WriteString(seg.Text)
+		case SegmentCatchAll:
+			b.WriteByte('*')
+		default:
+			b.WriteByte('{')
+			if names {
+				b.WriteString(seg.Name)
+			}
+			if seg.Type == SegmentRegexp {
+				b.WriteString(":" + seg.Regexp)
+			}
+			b.WriteByte('}')
+		}
+	}
+	return b.String()
+}
+
+// Match reports whether the router matches the routing `path` with the
+// pattern, and returns the URL params of the path.
+func (p Pattern) Match(path string) (RouteParams, bool) {
+	tree := p.tree
+	if tree == nil {
+		tree = &node{}
+		tree.InsertRoute(mGET, p.Format(), http.NotFoundHandler())
+	}
+
+	rctx := NewRouteContext()
+	if _, _, h := tree.FindRoute(rctx, mGET, path); h == nil {
+		return RouteParams{}, false
+	}
+	return rctx.URLParams, true
+}
+
+// Expand returns the path of the pattern with its params replaced by the
+// `params` values, keyed by param name. The values are inserted as given,
+// so escape them with url.PathEscape as needed. It returns an error if a
+// value is missing, other than the catch-all one, contains a slash, other
+// than the catch-all one, or doesn't match the regexp of its param.
+func (p Pattern) Expand(params map[string]string) (string, error) {
+	var b strings.Builder
+	for _, seg := range p.Segments {
+		if seg.Type == SegmentStatic {
+			b.WriteString(seg.Text)
+			continue
+		}
+
+		value, ok := params[seg.Name]
+		if seg.Type == SegmentCatchAll {
+			b.WriteString(value)
+			continue
+		}
+		if !ok {
+			return "", fmt.Errorf("chi: missing value for param '%s' of pattern '%s'", seg.Name, p.Format())
+		}
+		if strings.Contains(value, "/") {
+			return "", fmt.Errorf("chi: value '%s' of param '%s' contains a slash", value, seg.Name)
+		}
+		if seg.Type == SegmentRegexp {
+			rex, err := regexp.Compile(anchorRegexp(seg.Regexp))
+			if err != nil {
+				return "", fmt.Errorf("chi: invalid regexp pattern '%s' in route param", seg.Regexp)
+			}
+			if !rex.MatchString(value) {
+				return "", fmt.Errorf("chi: value '%s' of param '%s' doesn't match '%s'", value, seg.Name, seg.Regexp)
+			}
+		}
+		b.WriteString(value)
+	}
+	return b.String(), nil
+}
+
+// anchorRegexp anchors a param regexp the way the router does.
+func anchorRegexp(rexpat string) string {
+	if rexpat == "" {
+		return rexpat
+	}
+	if rexpat[0] != '^' {
+		rexpat = "^" + rexpat
+	}
+	if rexpat[len(rexpat)-1] != '$' {
+		rexpat += "$"
+	}
+	return rexpat
+}
@@ -3,6 +3,7 @@ package chi
 import (
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 )
 
@@ -65,3 +66,100 @@ func TestPattern(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePattern(t *testing.T) {
+	p, err := ParsePattern("/users/{id:[0-9]+}/posts/{slug}-{lang:[a-z]{2}}/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Segment{
+		{Type: SegmentStatic, Text: "/users/"},
+		{Type: SegmentRegexp, Name: "id", Regexp: "[0-9]+"},
+		{Type: SegmentStatic, Text: "/posts/"},
+		{Type: SegmentParam, Name: "slug"},
+		{Type: SegmentStatic, Text: "-"},
+		{Type: SegmentRegexp, Name: "lang", Regexp: "[a-z]{2}"},
+		{Type: SegmentStatic, Text: "/"},
+		{Type: SegmentCatchAll, Name: "*"},
+	}
+	if !slices.Equal(p.Segments, want) {
+		t.Fatalf("unexpected segments:\n%+v\nexpected:\n%+v", p.Segments, want)
+	}
+	if got := p.ParamNames(); !slices.Equal(got, []string{"id", "slug", "lang", "*"}) {
+		t.Fatalf("unexpected param names %q", got)
+	}
+	if got := p.CatchAll(); got != 7 {
+		t.Fatalf("expected the catch-all param at 7, got %d", got)
+	}
+	if got := p.Format(); got != "/users/{id:[0-9]+}/posts/{slug}-{lang:[a-z]{2}}/*" {
+		t.Fatalf("unexpected format %q", got)
+	}
+	if got := p.Normalize(); got != "/users/{:[0-9]+}/posts/{}-{:[a-z]{2}}/*" {
+		t.Fatalf("unexpected normalized pattern %q", got)
+	}
+
+	params, ok := p.Match("/users/1/posts/hello-en/a/b")
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	if !slices.Equal(params.Keys, []string{"id", "slug", "lang", "*"}) || !slices.Equal(params.Values, []string{"1", "hello", "en", "a/b"}) {
+		t.Fatalf("unexpected params %+v", params)
+	}
+	for _, path := range []string{"/users/x/posts/hello-en/a", "/users/1/posts/hello-eng/a", "/users/1"} {
+		if _, ok := p.Match(path); ok {
+			t.Fatalf("expected no match for %s", path)
+		}
+	}
+
+	path, err := p.Expand(map[string]string{"id": "2", "slug": "bye", "lang": "fr", "*": "c/d"})
+	if err != nil || path != "/users/2/posts/bye-fr/c/d" {
+		t.Fatalf("unexpected expansion %q, %v", path, err)
+	}
+	for _, params := range []map[string]string{
+		{"slug": "bye", "lang": "fr"},
+		{"id": "x", "slug": "bye", "lang": "fr"},
+		{"id": "2", "slug": "b/ye", "lang": "fr"},
+	} {
+		if _, err := p.Expand(params); err == nil {
+			t.Fatalf("expected an error expanding %v", params)
+		}
+	}
+}
+
+func TestParsePatternErrors(t *testing.T) {
+	for _, pattern := range []string{
+		"users",
+		"/users/{id",
+		"/users/{id:[0-9}",
+		"/users/{id}/{id}",
+		"/users/*/posts",
+		"/*/{id}",
+	} {
+		if _, err := ParsePattern(pattern); err == nil {
+			t.Errorf("expected an error parsing %q", pattern)
+		}
+	}
+}
+
+func TestParsePatternMatchesRouter(t *testing.T) {
+	for _, pattern := range []string{"/", "/users/{id}", "/files/*", "/a/{b:[0-9]+}.json"} {
+		p, err := ParsePattern(pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Format() != pattern {
+			t.Fatalf("expected %q, got %q", pattern, p.Format())
+		}
+
+		// A Pattern built by hand matches the same paths.
+		built := Pattern{Segments: p.Segments}
+		for _, path := range []string{"/", "/users/1", "/files/a/b", "/a/12.json", "/a/x.json"} {
+			_, got := built.Match(path)
+			_, want := p.Match(path)
+			if got != want {
+				t.Errorf("%s: expected match %v for %s, got %v", pattern, want, path, got)
+			}
+		}
+	}
+}
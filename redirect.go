@@ -0,0 +1,197 @@
+package chi
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Redirect registers a route on `from` redirecting requests of any method to
+// `to`, with the redirect status `code`, for example:
+//
+//	chi.Redirect(r, "/u/{id}", "/users/{id}", http.StatusPermanentRedirect)
+//
+// The URL params of `from` are substituted in `to`, which may be a path or
+// an absolute URL, and the query string of the request is preserved. The
+// route shows up in Routes() and Walk() like any other route, with the
+// target and code in its metadata under the MetaRedirectTo and
+// MetaRedirectCode keys.
+func Redirect(r Router, from, to string, code int) {
+	if code < 300 || code > 399 {
+		panic(fmt.Sprintf("chi: Redirect() from '%s' with a non-redirect status code %d", from, code))
+	}
+
+	// Split the scheme and host of an absolute URL off its path.
+	origin, path := "", to
+	if i := strings.Index(to, "://"); i >= 0 {
+		origin, path = to, ""
+		if j := strings.IndexByte(to[i+3:], '/'); j >= 0 {
+			origin, path = to[:i+3+j], to[i+3+j:]
+		}
+	}
+
+	var target Pattern
+	if path != "" {
+		var err error
+		if target, err = ParsePattern(path); err != nil {
+			panic(err.Error())
+		}
+	}
+	fromParams := patParamKeys(from)
+	for _, name := range target.ParamNames() {
+		if !slices.Contains(fromParams, name) {
+			panic(fmt.Sprintf("chi: Redirect() target '%s' uses param '%s', missing from '%s'", to, name, from))
+		}
+	}
+
+	h := &redirectHandler{origin: origin, target: target, code: code}
+	r.Handle(from, WithMetadata(h, Metadata{
+		MetaRedirectTo:   to,
+		MetaRedirectCode: code,
+	}))
+}
+
+type redirectHandler struct {
+	origin string
+	target Pattern
+	code   int
+}
+
+func (h *redirectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path, err := h.target.Expand(urlParamValues(r, h.target))
+	if err != nil {
+		// The captured values don't fit the target, so it doesn't exist.
+		http.NotFound(w, r)
+		return
+	}
+
+	// Collapse the leading slashes of a path substituted from the request, so
+	// the target can't turn into a protocol-relative URL to another host.
+	if len(path) > 1 && (path[1] == '/' || path[1] == '\\') {
+		path = "/" + strings.TrimLeft(path, "/\\")
+	}
+
+	url := h.origin + path
+	if r.URL.RawQuery != "" {
+		sep := "?"
+		if strings.Contains(url, "?") {
+			sep = "&"
+		}
+		url += sep + r.URL.RawQuery
+	}
+	http.Redirect(w, r, url, h.code)
+}
+
+// Alias registers the route `pattern` as an alias of the route `canonical`
+// of the router, serving requests with the same handlers and inline
+// middlewares, for every method of the canonical route, for example:
+//
+//	r.Get("/users/{id}", getUser)
+//	chi.Alias(r, "/members/{id}", "/users/{id}", true)
+//
+// The canonical route must be registered on `r` beforehand, and the params
+// of its pattern must be in `pattern` too. When `canonicalLink` is set, the
+// responses of the alias carry a `Link: <...>; rel="canonical"` header with
+// the canonical path. The alias has the canonical pattern in its metadata,
+// under the MetaAliasOf key.
+func Alias(r Router, pattern, canonical string, canonicalLink bool) {
+	var handlers map[string]http.Handler
+	for _, route := range r.Routes() {
+		if route.Pattern == canonical && route.SubRoutes == nil {
+			handlers = route.Handlers
+			break
+		}
+	}
+	if handlers == nil {
+		panic(fmt.Sprintf("chi: Alias() of '%s' to '%s', which isn't a route of the router", pattern, canonical))
+	}
+
+	target, err := ParsePattern(canonical)
+	if err != nil {
+		panic(err.Error())
+	}
+	aliasParams := patParamKeys(pattern)
+	for _, name := range target.ParamNames() {
+		if !slices.Contains(aliasParams, name) {
+			panic(fmt.Sprintf("chi: Alias() of '%s' to '%s' is missing the param '%s'", pattern, canonical, name))
+		}
+	}
+
+	// Register the handlers on the mux owning the routing tree, as they're
+	// already wrapped by the inline middlewares of the canonical route.
+	handle := func(method, pattern string, h http.Handler) {
+		if method == "*" {
+			r.Handle(pattern, h)
+		} else {
+			r.Method(method, pattern, h)
+		}
+	}
+	if mx, ok := r.(*Mux); ok {
+		tm := mx.treeMux()
+		handle = func(method, pattern string, h http.Handler) {
+			mt := mALL
+			if method != "*" {
+				mt, _ = tm.methodTyp(method)
+			}
+			tm.handle(mt, pattern, h)
+		}
+	}
+
+	// Register the handler for all methods first, so it doesn't override
+	// the handlers of specific methods, which it already covers when they
+	// share the handler.
+	all, ok := handlers["*"]
+	if ok {
+		handle("*", pattern, aliasOf(all, target, canonicalLink))
+	}
+	for method, h := range handlers {
+		if method != "*" && !(ok && equalHandlers(h, all)) {
+			handle(method, pattern, aliasOf(h, target, canonicalLink))
+		}
+	}
+}
+
+// aliasOf wraps the endpoint of a route handler into an aliasHandler.
+func aliasOf(h http.Handler, canonical Pattern, canonicalLink bool) http.Handler {
+	if chain, ok := h.(*ChainHandler); ok {
+		return Chain(chain.Middlewares...).Handler(aliasOf(chain.Endpoint, canonical, canonicalLink))
+	}
+	return &aliasHandler{handler: h, canonical: canonical, link: canonicalLink}
+}
+
+type aliasHandler struct {
+	handler   http.Handler
+	canonical Pattern
+	link      bool
+}
+
+func (h *aliasHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.link {
+		if path, err := h.canonical.Expand(urlParamValues(r, h.canonical)); err == nil {
+			w.Header().Add("Link", "<"+path+`>; rel="canonical"`)
+		}
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+// Metadata returns the metadata of the aliased handler, along with the
+// canonical pattern under the MetaAliasOf key.
+func (h *aliasHandler) Metadata() Metadata {
+	md := Metadata{}
+	for k, v := range HandlerMetadata(h.handler) {
+		md[k] = v
+	}
+	md[MetaAliasOf] = h.canonical.Format()
+	return md
+}
+
+// urlParamValues returns the values of the URL params of the request for
+// the params of the pattern.
+func urlParamValues(r *http.Request, p Pattern) map[string]string {
+	values := map[string]string{}
+	for _, name := range p.ParamNames() {
+		values[name] = URLParam(r, name)
+	}
+	return values
+}
@@ -0,0 +1,153 @@
+package chi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedirect(t *testing.T) {
+	r := NewRouter()
+	Redirect(r, "/u/{id}", "/users/{id}", http.StatusPermanentRedirect)
+	Redirect(r, "/old/*", "/new/*", http.StatusMovedPermanently)
+	Redirect(r, "/docs", "https://docs.example.com", http.StatusFound)
+	Redirect(r, "/legacy/*", "/*", http.StatusMovedPermanently)
+	r.Route("/v1", func(r Router) {
+		Redirect(r, "/orgs/{org}/repos/{repo}", "https://example.com/{org}/{repo}?tab=code", http.StatusFound)
+	})
+
+	tests := []struct {
+		method, path string
+		code         int
+		location     string
+	}{
+		{"GET", "/u/42", 308, "/users/42"},
+		{"POST", "/u/42?a=1&b=2", 308, "/users/42?a=1&b=2"},
+		{"GET", "/old/a/b.html", 301, "/new/a/b.html"},
+		{"GET", "/legacy/a", 301, "/a"},
+		{"GET", "/legacy//evil.com", 301, "/evil.com"},
+		{"GET", "/legacy/%5Cevil.com", 301, "/evil.com"},
+		{"GET", "/legacy/%2F%2Fevil.com", 301, "/%2F%2Fevil.com"},
+		{"GET", "/docs?q=x", 302, "https://docs.example.com?q=x"},
+		{"GET", "/v1/orgs/go-chi/repos/chi?ref=main", 302, "https://example.com/go-chi/chi?tab=code&ref=main"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.code || w.Header().Get("Location") != tt.location {
+			t.Errorf("%s %s: expected %d %q, got %d %q", tt.method, tt.path, tt.code, tt.location, w.Code, w.Header().Get("Location"))
+		}
+	}
+
+	var found bool
+	Walk(r, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if method == "GET" && route == "/u/{id}" {
+			md := HandlerMetadata(handler)
+			found = md[MetaRedirectTo] == "/users/{id}" && md[MetaRedirectCode] == 308
+		}
+		return nil
+	})
+	if !found {
+		t.Fatal("expected the redirect route with its metadata in Walk")
+	}
+}
+
+func TestRedirectPanics(t *testing.T) {
+	tests := []struct {
+		from, to string
+		code     int
+	}{
+		{"/u/{id}", "/users/{id}", http.StatusOK},
+		{"/u/{id}", "/users/{userID}", http.StatusFound},
+		{"/u/{id}", "/users/{id", http.StatusFound},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic redirecting %s to %s", tt.from, tt.to)
+				}
+			}()
+			Redirect(NewRouter(), tt.from, tt.to, tt.code)
+		}()
+	}
+}
+
+func TestAlias(t *testing.T) {
+	mw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Inline", "1")
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	r := NewRouter()
+	r.With(mw).Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("user " + URLParam(r, "id")))
+	})
+	r.Delete("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("deleted " + URLParam(r, "id")))
+	})
+	r.Handle("/ping", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pong"))
+	}))
+	r.Group(func(r Router) {
+		Alias(r, "/members/{id}", "/users/{id}", true)
+	})
+	Alias(r, "/healthz", "/ping", false)
+
+	tests := []struct {
+		method, path string
+		body, link   string
+		inline       string
+	}{
+		{"GET", "/members/1", "user 1", `</users/1>; rel="canonical"`, "1"},
+		{"DELETE", "/members/2", "deleted 2", `</users/2>; rel="canonical"`, ""},
+		{"POST", "/healthz", "pong", "", ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Body.String() != tt.body || w.Header().Get("Link") != tt.link || w.Header().Get("X-Inline") != tt.inline {
+			t.Errorf("%s %s: unexpected response %q, Link %q, X-Inline %q", tt.method, tt.path, w.Body.String(), w.Header().Get("Link"), w.Header().Get("X-Inline"))
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/members/1", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status 405, got %d", w.Code)
+	}
+
+	var aliasOf string
+	var inline int
+	Walk(r, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if method == "GET" && route == "/members/{id}" {
+			aliasOf, _ = HandlerMetadata(handler)[MetaAliasOf].(string)
+			inline = len(middlewares)
+		}
+		return nil
+	})
+	if aliasOf != "/users/{id}" || inline != 1 {
+		t.Fatalf("unexpected alias route in Walk, alias of %q with %d middlewares", aliasOf, inline)
+	}
+}
+
+func TestAliasPanics(t *testing.T) {
+	r := NewRouter()
+	r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+	for _, tt := range [][2]string{
+		{"/members/{id}", "/people/{id}"},
+		{"/members/{memberID}", "/users/{id}"},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic aliasing %s to %s", tt[0], tt[1])
+				}
+			}()
+			Alias(r, tt[0], tt[1], false)
+		}()
+	}
+}
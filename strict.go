@@ -0,0 +1,83 @@
+package chi
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+)
+
+// Strict makes the router, and the sub-routers mounted on it, panic on
+// registration mistakes otherwise silently accepted, or reported without
+// context:
+//
+//   - a route registered for a method and pattern already registered, such
+//     as a Get after a Handle, or a Mount, of the same pattern;
+//   - Use called after routes are registered, which is otherwise allowed
+//     until the router serves its first request;
+//   - With called without middlewares, or with a nil middleware.
+//
+// The panic message has the file and line of the offending call, and of the
+// earlier registration it conflicts with. Registrations made before Strict
+// is called have no known location.
+func (mx *Mux) Strict() {
+	tm := mx.treeMux()
+	tm.strict = true
+	tm.updateSubRoutes(func(subMux *Mux) {
+		subMux.Strict()
+	})
+}
+
+// checkRoute panics if registering `h` for `method` on `pattern` overrides
+// a route of the router.
+func (mx *Mux) checkRoute(method methodTyp, pattern string, h http.Handler, site string) {
+	n := mx.tree.findPatternNode(pattern)
+	if n == nil {
+		return
+	}
+
+	methods := []methodTyp{method &^ mSTUB}
+	if method&mALL == mALL {
+		methods = []methodTyp{mALL}
+		for _, m := range methodMap {
+			methods = append(methods, m)
+		}
+	}
+
+	for _, m := range methods {
+		e := n.endpoints[m]
+		if e == nil || e.handler == nil || e.stub {
+			continue
+		}
+		// Routes of several versions share the handler dispatching to them,
+		// which falls back to the route registered outside of Version.
+		if vh, ok := h.(*versionHandler); ok && (e.handler == http.Handler(vh) || equalHandlers(e.handler, vh.fallback)) {
+			continue
+		}
+		name := "*"
+		if method&mALL != mALL {
+			name = mx.methodName(m)
+		}
+		prev := e.site
+		if prev == "" {
+			prev = "an unknown location"
+		}
+		panic(fmt.Sprintf("chi: route '%s %s' registered at %s overrides the route '%s' registered at %s", name, pattern, site, e.pattern, prev))
+	}
+}
+
+// callerSite returns the file and line of the caller registering a route
+// or middleware, outside of this package.
+func callerSite() string {
+	for skip := 1; ; skip++ {
+		pc, file, line, ok := runtime.Caller(skip)
+		if !ok {
+			return "an unknown location"
+		}
+		fn := runtime.FuncForPC(pc)
+		if fn != nil && strings.HasPrefix(fn.Name(), "github.com/go-chi/chi/v5.") && !strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		return fmt.Sprintf("%s:%d", file, line)
+	}
+}
@@ -0,0 +1,107 @@
+package chi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestStrict(t *testing.T) {
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	mw := func(next http.Handler) http.Handler { return next }
+
+	tests := []struct {
+		name  string
+		fn    func(r *Mux)
+		panic string
+	}{
+		{"duplicate", func(r *Mux) {
+			r.Get("/users", ok)
+			r.Get("/users", ok)
+		}, "route 'GET /users' registered at "},
+		{"override all methods", func(r *Mux) {
+			r.Handle("/users", http.HandlerFunc(ok))
+			r.Post("/users", ok)
+		}, "overrides the route '/users' registered at "},
+		{"override a method", func(r *Mux) {
+			r.Get("/users/{id}", ok)
+			r.Handle("/users/{name}", http.HandlerFunc(ok))
+		}, "route '* /users/{name}'"},
+		{"sub-router", func(r *Mux) {
+			r.Route("/api", func(r Router) {
+				r.Get("/", ok)
+				r.Group(func(r Router) {
+					r.Get("/", ok)
+				})
+			})
+		}, "route 'GET /'"},
+		{"mount over a route", func(r *Mux) {
+			r.Get("/admin", ok)
+			r.Mount("/admin", NewRouter())
+		}, "route '* /admin'"},
+		{"use after routes", func(r *Mux) {
+			r.Get("/", ok)
+			r.Use(mw)
+		}, "after the route '/' registered at "},
+		{"use after a group route", func(r *Mux) {
+			r.Group(func(r Router) {
+				r.Get("/", ok)
+			})
+			r.Use(mw)
+		}, "after the route '/' registered at "},
+		{"empty with", func(r *Mux) {
+			r.With().Get("/", ok)
+		}, "without middlewares"},
+		{"nil middleware", func(r *Mux) {
+			r.With(mw, nil).Get("/", ok)
+		}, "with a nil middleware"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					t.Fatal("expected panic()")
+				}
+				msg := fmt.Sprint(rec)
+				if !strings.Contains(msg, tt.panic) {
+					t.Fatalf("expected a panic containing %q, got %q", tt.panic, msg)
+				}
+				if !strings.Contains(msg, "strict_test.go:") {
+					t.Fatalf("expected the panic to locate the registration, got %q", msg)
+				}
+			}()
+			r := NewRouter()
+			r.Strict()
+			tt.fn(r)
+		})
+	}
+}
+
+func TestStrictAllowed(t *testing.T) {
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewRouter()
+	r.Strict()
+	r.Get("/users", ok)
+	r.Post("/users", ok)
+	r.Group(func(r Router) {
+		r.Get("/orders", ok)
+	})
+	r.Mount("/admin", NewRouter())
+	r.Get("/admin", ok)
+	r.Get("/items", ok)
+	r.Version("2", func(r Router) {
+		r.Get("/items", ok)
+	})
+	r.Version("3", func(r Router) {
+		r.Get("/items", ok)
+	})
+	Alias(r, "/people", "/users", false)
+
+	// Registrations before Strict aren't checked.
+	r = NewRouter()
+	r.Get("/", ok)
+	r.Get("/", ok)
+}
@@ -5,6 +5,7 @@ package chi
 // (MIT licensed). It's been heavily modified for use as a HTTP routing tree.
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -31,6 +32,10 @@ const (
 	mTRACE
 )
 
+// mCUSTOM flags the method types of the custom methods registered on a
+// router, see Mux#RegisterMethod.
+const mCUSTOM methodTyp = 1 << (strconv.IntSize - 1)
+
 var mALL = mCONNECT | mDELETE | mGET | mHEAD |
 	mOPTIONS | mPATCH | mPOST | mPUT | mQUERY | mTRACE
 
@@ -66,7 +71,13 @@ var reverseMethodMap = map[methodTyp]string{
 }
 
 // RegisterMethod adds support for custom HTTP method handlers, available
-// via Router#Method and Router#MethodFunc
+// via Router#Method and Router#MethodFunc, on every router.
+//
+// Deprecated: RegisterMethod isn't safe for concurrent use and is limited to
+// a few dozen methods. Use Mux#RegisterMethod instead. The top bit of the
+// method types is reserved for the methods of Mux#RegisterMethod, so the
+// limit is one method lower than it used to be: strconv.IntSize-2 methods in
+// all, including the standard ones.
 func RegisterMethod(method string) {
 	if method == "" {
 		return
@@ -76,8 +87,8 @@ func RegisterMethod(method string) {
 		return
 	}
 	n := len(methodMap)
-	if n > strconv.IntSize-2 {
-		panic(fmt.Sprintf("chi: max number of methods reached (%d)", strconv.IntSize))
+	if n > strconv.IntSize-3 {
+		panic(fmt.Sprintf("chi: max number of methods reached (%d)", strconv.IntSize-2))
 	}
 	mt := methodTyp(2 << n)
 	methodMap[method] = mt
@@ -134,6 +145,16 @@ type endpoint struct {
 
 	// parameter keys recorded on handler nodes
 	paramKeys []string
+
+	// mux the endpoint was registered on, which may be an inline mux
+	mux *Mux
+
+	// stub is set on endpoints continuing the routing at a mounted handler
+	stub bool
+
+	// site is the file and line the endpoint was registered at, in strict
+	// mode
+	site string
 }
 
 func (s endpoints) Value(method methodTyp) *endpoint {
@@ -145,6 +166,39 @@ func (s endpoints) Value(method methodTyp) *endpoint {
 	return mh
 }
 
+// find returns the endpoint for `method`. A custom method without an
+// endpoint of its own falls back to the endpoint for all methods, and a
+// method unknown to the router to the endpoint of a mounted sub-router.
+func (s endpoints) find(method methodTyp) *endpoint {
+	if method == mUNKNOWN {
+		if e := s[mALL]; e != nil && e.stub {
+			return e
+		}
+		return nil
+	}
+	e := s[method]
+	if (e == nil || e.handler == nil) && method&mCUSTOM != 0 {
+		return s[mALL]
+	}
+	return e
+}
+
+// each calls fn for every endpoint set by a registration for `method`,
+// which may combine several method types, like mALL.
+func (s endpoints) each(method methodTyp, fn func(e *endpoint)) {
+	if method&mSTUB == mSTUB {
+		fn(s.Value(mSTUB))
+	}
+	if method&mALL == mALL {
+		fn(s.Value(mALL))
+		for _, m := range methodMap {
+			fn(s.Value(m))
+		}
+	} else if m := method &^ mSTUB; m != 0 {
+		fn(s.Value(m))
+	}
+}
+
 func (n *node) InsertRoute(method methodTyp, pattern string, handler http.Handler) *node {
 	var parent *node
 	search := pattern
@@ -361,30 +415,36 @@ func (n *node) setEndpoint(method methodTyp, handler http.Handler, pattern strin
 	if method&mSTUB == mSTUB {
 		n.endpoints.Value(mSTUB).handler = handler
 	}
+	stub := method&mSTUB == mSTUB
 	if method&mALL == mALL {
 		h := n.endpoints.Value(mALL)
 		h.handler = handler
 		h.pattern = pattern
 		h.paramKeys = paramKeys
+		h.stub = stub
 		for _, m := range methodMap {
 			h := n.endpoints.Value(m)
 			h.handler = handler
 			h.pattern = pattern
 			h.paramKeys = paramKeys
+			h.stub = stub
 		}
 	} else {
 		h := n.endpoints.Value(method)
 		h.handler = handler
 		h.pattern = pattern
 		h.paramKeys = paramKeys
+		h.stub = stub
 	}
 }
 
 func (n *node) FindRoute(rctx *Context, method methodTyp, path string) (*node, endpoints, http.Handler) {
-	// Reset the context routing pattern and params
+	// Reset the context routing pattern, params and endpoint
 	rctx.routePattern = ""
 	rctx.routeParams.Keys = rctx.routeParams.Keys[:0]
 	rctx.routeParams.Values = rctx.routeParams.Values[:0]
+	rctx.endpoint = nil
+	rctx.endpoints = nil
 
 	// Find the routing handlers for the path
 	rn := n.findRoute(rctx, method, path)
@@ -396,13 +456,15 @@ func (n *node) FindRoute(rctx *Context, method methodTyp, path string) (*node, e
 	rctx.URLParams.Keys = append(rctx.URLParams.Keys, rctx.routeParams.Keys...)
 	rctx.URLParams.Values = append(rctx.URLParams.Values, rctx.routeParams.Values...)
 
-	// Record the routing pattern in the request lifecycle
-	if rn.endpoints[method].pattern != "" {
-		rctx.routePattern = rn.endpoints[method].pattern
+	// Record the matched endpoint and routing pattern in the request lifecycle
+	rctx.endpoint = rn.endpoints.find(method)
+	rctx.endpoints = rn.endpoints
+	if rctx.endpoint.pattern != "" {
+		rctx.routePattern = rctx.endpoint.pattern
 		rctx.RoutePatterns = append(rctx.RoutePatterns, rctx.routePattern)
 	}
 
-	return rn, rn.endpoints, rn.endpoints[method].handler
+	return rn, rn.endpoints, rctx.endpoint.handler
 }
 
 // Recursive edge traversal by checking all nodeTyp groups along the way.
@@ -469,7 +531,7 @@ func (n *node) findRoute(rctx *Context, method methodTyp, path string) *node {
 
 				if len(xsearch) == 0 {
 					if xn.isLeaf() {
-						h := xn.endpoints[method]
+						h := xn.endpoints.find(method)
 						if h != nil && h.handler != nil {
 							rctx.routeParams.Keys = append(rctx.routeParams.Keys, h.paramKeys...)
 							return xn
@@ -517,7 +579,7 @@ func (n *node) findRoute(rctx *Context, method methodTyp, path string) *node {
 		// did we find it yet?
 		if len(xsearch) == 0 {
 			if xn.isLeaf() {
-				h := xn.endpoints[method]
+				h := xn.endpoints.find(method)
 				if h != nil && h.handler != nil {
 					rctx.routeParams.Keys = append(rctx.routeParams.Keys, h.paramKeys...)
 					return xn
@@ -589,6 +651,11 @@ func (n *node) isLeaf() bool {
 }
 
 func (n *node) findPattern(pattern string) bool {
+	return n.findPatternNode(pattern) != nil
+}
+
+// findPatternNode returns the node of the routing `pattern`, or nil.
+func (n *node) findPatternNode(pattern string) *node {
 	nn := n
 	for _, nds := range nn.children {
 		if len(nds) == 0 {
@@ -622,15 +689,15 @@ func (n *node) findPattern(pattern string) bool {
 
 		xpattern = pattern[idx:]
 		if len(xpattern) == 0 {
-			return true
+			return n
 		}
 
-		return n.findPattern(xpattern)
+		return n.findPatternNode(xpattern)
 	}
-	return false
+	return nil
 }
 
-func (n *node) routes() []Route {
+func (n *node) routes(methodName func(methodTyp) string) []Route {
 	rts := []Route{}
 
 	n.walk(func(eps endpoints, subroutes Routes) bool {
@@ -670,7 +737,7 @@ func (n *node) routes() []Route {
 				if h.handler == nil || equalHandlers(h.handler, stubHandler) {
 					continue
 				}
-				if m, ok := reverseMethodMap[mt]; ok {
+				if m := methodName(mt); m != "" {
 					hs[m] = h.handler
 				}
 			}
@@ -733,16 +800,26 @@ func (n *node) walk(fn func(eps endpoints, subroutes Routes) bool) bool {
 // patNextSegment returns the next segment details from a pattern:
 // node type, param key, regexp string, param tail byte, param starting index, param ending index
 func patNextSegment(pattern string) (nodeTyp, string, string, byte, int, int) {
+	ntyp, key, rexpat, tail, ps, pe, err := patParseSegment(pattern)
+	if err != nil {
+		panic(err.Error())
+	}
+	return ntyp, key, rexpat, tail, ps, pe
+}
+
+// patParseSegment is patNextSegment, returning an error for an invalid
+// pattern instead of panicking.
+func patParseSegment(pattern string) (nodeTyp, string, string, byte, int, int, error) {
 	ps := strings.Index(pattern, "{")
 	ws := strings.Index(pattern, "*")
 
 	if ps < 0 && ws < 0 {
-		return ntStatic, "", "", 0, 0, len(pattern) // we return the entire thing
+		return ntStatic, "", "", 0, 0, len(pattern), nil // we return the entire thing
 	}
 
 	// Sanity check
 	if ps >= 0 && ws >= 0 && ws < ps {
-		panic("chi: wildcard '*' must be the last pattern in a route, otherwise use a '{param}'")
+		return 0, "", "", 0, 0, 0, errors.New("chi: wildcard '*' must be the last pattern in a route, otherwise use a '{param}'")
 	}
 
 	var tail byte = '/' // Default endpoint tail to / byte
@@ -766,7 +843,7 @@ func patNextSegment(pattern string) (nodeTyp, string, string, byte, int, int) {
 			}
 		}
 		if pe == ps {
-			panic("chi: route param closing delimiter '}' is missing")
+			return 0, "", "", 0, 0, 0, errors.New("chi: route param closing delimiter '}' is missing")
 		}
 
 		key := pattern[ps+1 : pe]
@@ -781,23 +858,14 @@ func patNextSegment(pattern string) (nodeTyp, string, string, byte, int, int) {
 			nt = ntRegexp
 		}
 
-		if len(rexpat) > 0 {
-			if rexpat[0] != '^' {
-				rexpat = "^" + rexpat
-			}
-			if rexpat[len(rexpat)-1] != '$' {
-				rexpat += "$"
-			}
-		}
-
-		return nt, key, rexpat, tail, ps, pe
+		return nt, key, anchorRegexp(rexpat), tail, ps, pe, nil
 	}
 
 	// Wildcard pattern as finale
 	if ws < len(pattern)-1 {
-		panic("chi: wildcard '*' must be the last value in a route. trim trailing text or use a '{param}' instead")
+		return 0, "", "", 0, 0, 0, errors.New("chi: wildcard '*' must be the last value in a route. trim trailing text or use a '{param}' instead")
 	}
-	return ntCatchAll, "*", "", 0, ws, len(pattern)
+	return ntCatchAll, "*", "", 0, ws, len(pattern), nil
 }
 
 func patParamKeys(pattern string) []string {
@@ -818,6 +886,25 @@ func patParamKeys(pattern string) []string {
 	}
 }
 
+// patStaticPrefix returns the leading whole path segments of a pattern that
+// precede its first param or wildcard, without a trailing slash.
+func patStaticPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, "{*"); i >= 0 {
+		pattern = pattern[:strings.LastIndexByte(pattern[:i], '/')+1]
+	}
+	return strings.TrimSuffix(pattern, "/")
+}
+
+// commonSegmentPrefix returns the longest run of whole path segments shared
+// by the two paths.
+func commonSegmentPrefix(a, b string) string {
+	n := longestPrefix(a, b)
+	if (n == len(a) || a[n] == '/') && (n == len(b) || b[n] == '/') {
+		return a[:n]
+	}
+	return a[:max(strings.LastIndexByte(a[:n], '/'), 0)]
+}
+
 // longestPrefix finds the length of the shared prefix of two strings
 func longestPrefix(k1, k2 string) (i int) {
 	for i = 0; i < min(len(k1), len(k2)); i++ {
@@ -879,11 +966,30 @@ type Route struct {
 type WalkFunc func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error
 
 // Walk walks any router tree that implements Routes interface.
+//
+// The middlewares passed to walkFn are the full ordered stack that wraps
+// the route handler, including those inherited from parent routers through
+// Mount and Route, inline middlewares added with With or Group, and the
+// UseMatched middlewares of a Mux. Use Middlewares.Names to identify them.
 func Walk(r Routes, walkFn WalkFunc) error {
-	return walk(r, walkFn, "")
+	return walk(r, func(mount, method, route string, handler http.Handler, middlewares []func(http.Handler) http.Handler) error {
+		return walkFn(method, route, handler, middlewares...)
+	}, "", nil, nil)
 }
 
-func walk(r Routes, walkFn WalkFunc, parentRoute string, parentMw ...func(http.Handler) http.Handler) error {
+// walkRouteFunc is the function called for each method and route visited by
+// walk, along with the pattern of the sub-router the route was mounted from,
+// which is empty for the root router.
+type walkRouteFunc func(mount, method, route string, handler http.Handler, middlewares []func(http.Handler) http.Handler) error
+
+func walk(r Routes, walkFn walkRouteFunc, parentRoute string, parentMw, parentMatched []func(http.Handler) http.Handler) error {
+	mount := strings.TrimSuffix(strings.ReplaceAll(parentRoute, "/*/", "/"), "/*")
+
+	matched := parentMatched
+	if mx, ok := mountedMux(r); ok && len(mx.matchedMiddlewares) > 0 {
+		matched = slices.Concat(parentMatched, mx.matchedMiddlewares)
+	}
+
 	for _, route := range r.Routes() {
 		mws := slices.Concat(parentMw, r.Middlewares())
 
@@ -894,12 +1000,16 @@ func walk(r Routes, walkFn WalkFunc, parentRoute string, parentMw ...func(http.H
 				}
 			}
 
-			if err := walk(route.SubRoutes, walkFn, parentRoute+route.Pattern, mws...); err != nil {
+			if err := walk(route.SubRoutes, walkFn, parentRoute+route.Pattern, mws, matched); err != nil {
 				return err
 			}
 			continue
 		}
 
+		// UseMatched middlewares wrap the route handler, after the whole
+		// Use stack and before its inline middlewares.
+		mws = append(mws, matched...)
+
 		for method, handler := range route.Handlers {
 			if method == "*" {
 				// Ignore a "catchAll" method, since we pass down all the specific methods for each route.
@@ -910,11 +1020,11 @@ func walk(r Routes, walkFn WalkFunc, parentRoute string, parentMw ...func(http.H
 			fullRoute = strings.ReplaceAll(fullRoute, "/*/", "/")
 
 			if chain, ok := handler.(*ChainHandler); ok {
-				if err := walkFn(method, fullRoute, chain.Endpoint, append(mws, chain.Middlewares...)...); err != nil {
+				if err := walkFn(mount, method, fullRoute, chain.Endpoint, slices.Concat(mws, chain.Middlewares)); err != nil {
 					return err
 				}
 			} else {
-				if err := walkFn(method, fullRoute, handler, mws...); err != nil {
+				if err := walkFn(mount, method, fullRoute, handler, mws); err != nil {
 					return err
 				}
 			}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"slices"
+	"strings"
 	"testing"
 )
 
@@ -713,3 +715,125 @@ func TestWalkMiddlewaresAcrossGroupAndRoute(t *testing.T) {
 		}
 	}
 }
+
+func TestWalkNamedMiddlewares(t *testing.T) {
+	mw := func(next http.Handler) http.Handler { return next }
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewRouter()
+	r.Use(Named("requestID", mw))
+	r.UseMatched(Named("metrics", mw))
+	r.Get("/", handler)
+
+	r.Route("/api", func(r Router) {
+		r.Use(Named("auth", mw))
+		r.With(Named("ratelimit", mw)).Get("/users", handler)
+		r.Get("/health", handler)
+	})
+
+	sub := NewRouter()
+	sub.UseMatched(Named("audit", mw))
+	sub.Post("/", handler)
+	r.With(Named("auth", mw)).Mount("/admin", sub)
+
+	got := map[string][]string{}
+	if err := Walk(r, func(method, route string, handler http.Handler, mws ...func(http.Handler) http.Handler) error {
+		got[method+" "+route] = Middlewares(mws).Names()
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{
+		"GET /":           {"requestID", "metrics"},
+		"GET /api/users":  {"requestID", "auth", "metrics", "ratelimit"},
+		"GET /api/health": {"requestID", "auth", "metrics"},
+		"POST /admin/":    {"requestID", "auth", "metrics", "audit"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected routes %v, got %v", want, got)
+	}
+	for route, wantNames := range want {
+		if gotNames := got[route]; !slices.Equal(gotNames, wantNames) {
+			t.Fatalf("%s: expected middlewares %v, got %v", route, wantNames, gotNames)
+		}
+	}
+}
+
+func TestMiddlewareName(t *testing.T) {
+	var calls []string
+	mw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, "mw")
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	named := Named("logger", mw)
+	if name := MiddlewareName(named); name != "logger" {
+		t.Fatalf("expected name %q, got %q", "logger", name)
+	}
+	if name := MiddlewareName(Named("outer", named)); name != "outer" {
+		t.Fatalf("expected name %q, got %q", "outer", name)
+	}
+	if name := MiddlewareName(middlewareForNameTest); !strings.HasSuffix(name, ".middlewareForNameTest") {
+		t.Fatalf("expected the function name of an unnamed middleware, got %q", name)
+	}
+	if name := MiddlewareName(nil); name != "" {
+		t.Fatalf("expected an empty name for a nil middleware, got %q", name)
+	}
+
+	// The named middleware must behave exactly like the original.
+	r := NewRouter()
+	r.Use(named)
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if !slices.Equal(calls, []string{"mw", "handler"}) {
+		t.Fatalf("expected the named middleware to run, got %v", calls)
+	}
+}
+
+func middlewareForNameTest(next http.Handler) http.Handler { return next }
+
+func TestPatStaticPrefix(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"/", ""},
+		{"/{id}", ""},
+		{"/api", "/api"},
+		{"/api/", "/api"},
+		{"/api/*", "/api"},
+		{"/api/users/{id}", "/api/users"},
+		{"/api/u{id}", "/api"},
+		{"/api/{id:[0-9]+}/tags", "/api"},
+	}
+	for _, tt := range tests {
+		if got := patStaticPrefix(tt.pattern); got != tt.want {
+			t.Errorf("patStaticPrefix(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestCommonSegmentPrefix(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"", "/api", ""},
+		{"/api", "/api", "/api"},
+		{"/api", "/api/users", "/api"},
+		{"/api/users", "/api/orders", "/api"},
+		{"/api/users", "/api/user", "/api"},
+		{"/apiary", "/api", ""},
+		{"/a/b/c", "/a/b/d", "/a/b"},
+	}
+	for _, tt := range tests {
+		if got := commonSegmentPrefix(tt.a, tt.b); got != tt.want {
+			t.Errorf("commonSegmentPrefix(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
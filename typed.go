@@ -0,0 +1,264 @@
+package chi
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+)
+
+// JSON returns a http.Handler for the typed endpoint function `fn`.
+//
+// For every request, the handler decodes a JSON request body into a new In
+// value, and binds the URL params and query string values into the fields
+// of In tagged with `param:"name"` and `query:"name"`. It then calls `fn`
+// and writes the returned Out value as a JSON response, with the status
+// code returned by a `StatusCode() int` method of Out, or 200 otherwise.
+//
+// Decoding failures are reported as a *StatusError with a 400 or 415 code,
+// and together with any error returned by `fn`, are passed on to the
+// ErrorHandler of the router. For example:
+//
+//	type GetUserRequest struct {
+//		ID     int64    `param:"id"`
+//		Fields []string `query:"fields"`
+//	}
+//
+//	r.Method("GET", "/users/{id}", chi.JSON(func(ctx context.Context, req GetUserRequest) (*User, error) {
+//		return db.GetUser(ctx, req.ID, req.Fields)
+//	}))
+//
+// The In and Out types are available in the handler metadata, under the
+// MetaRequestType and MetaResponseType keys, for use by documentation
+// generators. Register the handler with Handle or Method to keep them.
+func JSON[In, Out any](fn func(ctx context.Context, in In) (Out, error)) http.Handler {
+	if fn == nil {
+		panic("chi: attempting to use a nil JSON endpoint function")
+	}
+	return &jsonHandler[In, Out]{fn: fn, fields: bindFields(reflect.TypeFor[In]())}
+}
+
+type jsonHandler[In, Out any] struct {
+	fn     func(ctx context.Context, in In) (Out, error)
+	fields []bindField
+}
+
+func (h *jsonHandler[In, Out]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	HandlerFuncE(h.serve).ServeHTTP(w, r)
+}
+
+func (h *jsonHandler[In, Out]) serve(w http.ResponseWriter, r *http.Request) error {
+	var in In
+	if err := decodeJSON(r, &in); err != nil {
+		return err
+	}
+	if err := bindValues(r, reflect.ValueOf(&in).Elem(), h.fields); err != nil {
+		return err
+	}
+
+	out, err := h.fn(r.Context(), in)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+
+	status := http.StatusOK
+	if sc, ok := any(out).(interface{ StatusCode() int }); ok {
+		status = sc.StatusCode()
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// endpointFunc returns the endpoint function, to name the handler after it.
+func (h *jsonHandler[In, Out]) endpointFunc() any {
+	return h.fn
+}
+
+func (h *jsonHandler[In, Out]) Metadata() Metadata {
+	return Metadata{
+		MetaRequestType:  reflect.TypeFor[In](),
+		MetaResponseType: reflect.TypeFor[Out](),
+	}
+}
+
+// decodeJSON decodes the JSON request body, if any, into v.
+func decodeJSON(r *http.Request, v any) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mt, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mt != "application/json" && !isJSONSuffix(mt)) {
+			return &StatusError{Code: http.StatusUnsupportedMediaType, Err: fmt.Errorf("chi: unsupported content type %q", ct)}
+		}
+	}
+
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err == nil || errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return &StatusError{Code: http.StatusRequestEntityTooLarge, Err: err}
+	}
+	return &StatusError{Code: http.StatusBadRequest, Err: fmt.Errorf("chi: invalid request body: %w", err)}
+}
+
+// isJSONSuffix reports whether the media type uses the +json structured
+// syntax suffix, as in application/problem+json.
+func isJSONSuffix(mt string) bool {
+	return len(mt) > 5 && mt[len(mt)-5:] == "+json"
+}
+
+// bindField is a struct field bound from a URL param or query value.
+type bindField struct {
+	index []int
+	name  string
+	query bool
+}
+
+// bindFields returns the fields of a struct type tagged for binding.
+func bindFields(t reflect.Type) []bindField {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []bindField
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() {
+			continue
+		}
+		if name, ok := f.Tag.Lookup("param"); ok {
+			fields = append(fields, bindField{index: f.Index, name: name})
+		} else if name, ok := f.Tag.Lookup("query"); ok {
+			fields = append(fields, bindField{index: f.Index, name: name, query: true})
+		}
+	}
+	return fields
+}
+
+// bindValues sets the tagged fields of the struct value v from the request.
+func bindValues(r *http.Request, v reflect.Value, fields []bindField) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	query := r.URL.Query()
+	for _, f := range fields {
+		var values []string
+		if f.query {
+			values = query[f.name]
+		} else if value := URLParam(r, f.name); value != "" {
+			// URL params hold the escaped path when the request URL has a
+			// RawPath, see Mux#routeHTTP.
+			if r.URL.RawPath != "" {
+				var err error
+				if value, err = url.PathUnescape(value); err != nil {
+					return &StatusError{Code: http.StatusBadRequest, Err: fmt.Errorf("chi: invalid url param %q: %w", f.name, err)}
+				}
+			}
+			values = []string{value}
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			return err
+		}
+		if err := setValue(fv, values); err != nil {
+			source := "url param"
+			if f.query {
+				source = "query value"
+			}
+			return &StatusError{Code: http.StatusBadRequest, Err: fmt.Errorf("chi: invalid %s %q: %w", source, f.name, err)}
+		}
+	}
+	return nil
+}
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// setValue parses values into v, which may be a basic type, a pointer to or
+// slice of one, or implement encoding.TextUnmarshaler.
+func setValue(v reflect.Value, values []string) error {
+	if reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(values[0]))
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		pv := reflect.New(v.Type().Elem())
+		if err := setValue(pv.Elem(), values); err != nil {
+			return err
+		}
+		v.Set(pv)
+		return nil
+
+	case reflect.Slice:
+		sv := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(sv.Index(i), []string{value}); err != nil {
+				return err
+			}
+		}
+		v.Set(sv)
+		return nil
+
+	case reflect.String:
+		v.SetString(values[0])
+		return nil
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(values[0])
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(values[0], 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+		return nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(values[0], 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(values[0], v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+		return nil
+	}
+
+	return fmt.Errorf("unsupported type %s", v.Type())
+}
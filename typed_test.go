@@ -0,0 +1,128 @@
+package chi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCreateRequest struct {
+	OrgID  int64      `param:"orgID"`
+	Notify bool       `query:"notify"`
+	Tags   []string   `query:"tag"`
+	Since  *time.Time `query:"since"`
+	Name   string     `json:"name"`
+}
+
+type testCreateResponse struct {
+	OrgID int64    `json:"orgID"`
+	Name  string   `json:"name"`
+	Tags  []string `json:"tags"`
+	Since string   `json:"since,omitempty"`
+}
+
+func (testCreateResponse) StatusCode() int { return http.StatusCreated }
+
+func TestJSON(t *testing.T) {
+	r := NewRouter()
+	r.Method("POST", "/orgs/{orgID}/teams", JSON(func(ctx context.Context, req testCreateRequest) (testCreateResponse, error) {
+		if req.Name == "taken" {
+			return testCreateResponse{}, &StatusError{Code: http.StatusConflict}
+		}
+		resp := testCreateResponse{OrgID: req.OrgID, Name: req.Name, Tags: req.Tags}
+		if req.Since != nil {
+			resp.Since = req.Since.Format(time.DateOnly)
+		}
+		return resp, nil
+	}))
+	r.Method("GET", "/ping", JSON(func(ctx context.Context, _ struct{}) (map[string]string, error) {
+		return map[string]string{"pong": URLParamFromCtx(ctx, "x")}, nil
+	}))
+	r.Method("GET", "/users/{name}", JSON(func(ctx context.Context, req struct {
+		Name string `param:"name"`
+	}) (map[string]string, error) {
+		return map[string]string{"name": req.Name}, nil
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		ctype  string
+		body   string
+		status int
+		resp   string
+	}{
+		{"decode and bind", "POST", "/orgs/7/teams?tag=a&tag=b&since=2024-05-01T00:00:00Z", "application/json", `{"name":"core"}`, 201,
+			`{"orgID":7,"name":"core","tags":["a","b"],"since":"2024-05-01"}` + "\n"},
+		{"no content type", "POST", "/orgs/7/teams", "", `{"name":"core"}`, 201, `{"orgID":7,"name":"core","tags":null}` + "\n"},
+		{"empty body", "GET", "/ping", "", "", 200, `{"pong":""}` + "\n"},
+		{"escaped param", "GET", "/users/100%25", "", "", 200, `{"name":"100%"}` + "\n"},
+		{"param escaping a percent", "GET", "/users/a%2525b", "", "", 200, `{"name":"a%25b"}` + "\n"},
+		{"param with a slash", "GET", "/users/a%2Fb", "", "", 200, `{"name":"a/b"}` + "\n"},
+		{"invalid param", "POST", "/orgs/seven/teams", "application/json", `{}`, 400, "Bad Request\n"},
+		{"invalid query", "POST", "/orgs/7/teams?since=yesterday", "application/json", `{}`, 400, "Bad Request\n"},
+		{"invalid body", "POST", "/orgs/7/teams", "application/json", `{"name":`, 400, "Bad Request\n"},
+		{"unsupported type", "POST", "/orgs/7/teams", "text/plain", `name`, 415, "Unsupported Media Type\n"},
+		{"handler error", "POST", "/orgs/7/teams", "application/json", `{"name":"taken"}`, 409, "Conflict\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.ctype != "" {
+				req.Header.Set("Content-Type", tt.ctype)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status || w.Body.String() != tt.resp {
+				t.Fatalf("expected %d %q, got %d %q", tt.status, tt.resp, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestJSONErrorHandler(t *testing.T) {
+	var got error
+
+	r := NewRouter()
+	r.ErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		got = err
+		w.WriteHeader(http.StatusTeapot)
+	})
+	r.Method("GET", "/", JSON(func(ctx context.Context, _ struct{}) (string, error) {
+		return "", errors.New("kettle")
+	}))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusTeapot || got == nil || got.Error() != "kettle" {
+		t.Fatalf("expected error to reach the error handler, got %d %v", w.Code, got)
+	}
+}
+
+func TestJSONMetadata(t *testing.T) {
+	r := NewRouter()
+	r.With(func(next http.Handler) http.Handler { return next }).Method("POST", "/orgs/{orgID}/teams",
+		JSON(func(ctx context.Context, req testCreateRequest) (testCreateResponse, error) {
+			return testCreateResponse{}, nil
+		}))
+
+	var md Metadata
+	Walk(r, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		md = HandlerMetadata(handler)
+		return nil
+	})
+
+	if md[MetaRequestType] != reflect.TypeFor[testCreateRequest]() {
+		t.Fatalf("unexpected request type %v", md[MetaRequestType])
+	}
+	if md[MetaResponseType] != reflect.TypeFor[testCreateResponse]() {
+		t.Fatalf("unexpected response type %v", md[MetaResponseType])
+	}
+}
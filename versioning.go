@@ -0,0 +1,299 @@
+package chi
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// VersionSource resolves the API version requested by a request, given the
+// routing path of the router. It returns the version, or "" if the request
+// doesn't ask for one, and the routing path to continue routing with.
+type VersionSource func(r *http.Request, routePath string) (version, nextPath string)
+
+// VersionFromHeader returns a VersionSource reading the version from the
+// request header `name`, as in "Api-Version: 2024-05-01".
+func VersionFromHeader(name string) VersionSource {
+	return func(r *http.Request, routePath string) (string, string) {
+		return strings.TrimSpace(r.Header.Get(name)), routePath
+	}
+}
+
+// VersionFromMediaType returns a VersionSource reading the version from the
+// vendor media types of the Accept header, in the
+// "application/vnd.<vendor>.<version>+json" form, or in the
+// "application/vnd.<vendor>+json; version=<version>" form.
+func VersionFromMediaType(vendor string) VersionSource {
+	prefix := "application/vnd." + vendor
+	return func(r *http.Request, routePath string) (string, string) {
+		for _, accept := range r.Header.Values("Accept") {
+			for _, mediaType := range strings.Split(accept, ",") {
+				mt, params, err := mime.ParseMediaType(mediaType)
+				if err != nil || !strings.HasPrefix(mt, prefix) {
+					continue
+				}
+				rest, _, _ := strings.Cut(mt[len(prefix):], "+")
+				if version, ok := strings.CutPrefix(rest, "."); ok && version != "" {
+					return version, routePath
+				}
+				if rest == "" && params["version"] != "" {
+					return params["version"], routePath
+				}
+			}
+		}
+		return "", routePath
+	}
+}
+
+// VersionFromPath returns a VersionSource reading the version from the first
+// segment of the routing path when it matches `re`, as in "/v2/users" for
+// `^v[0-9]+$`. The segment is removed from the routing path, so the routes
+// are registered without it.
+func VersionFromPath(re *regexp.Regexp) VersionSource {
+	return func(r *http.Request, routePath string) (string, string) {
+		segment, rest, _ := strings.Cut(strings.TrimPrefix(routePath, "/"), "/")
+		if segment == "" || !re.MatchString(segment) {
+			return "", routePath
+		}
+		return segment, "/" + rest
+	}
+}
+
+// VersionFallback selects the version of a route serving a request when the
+// route has no version matching the requested one exactly.
+type VersionFallback int
+
+const (
+	// VersionLatestCompatible serves the latest version of the route that
+	// is older than the requested version.
+	VersionLatestCompatible VersionFallback = iota
+
+	// VersionLatest serves the latest version of the route.
+	VersionLatest
+
+	// VersionExact only serves the requested version.
+	VersionExact
+)
+
+// VersionOptions configures how the Versioning middleware resolves the
+// version of the routes registered with Mux#Version.
+type VersionOptions struct {
+	// Source resolves the version requested by a request.
+	Source VersionSource
+
+	// Default is the version of requests which don't ask for one. If empty,
+	// these requests are served by the latest version of each route.
+	Default string
+
+	// Fallback selects the version serving a request when a route has no
+	// version matching the requested one. It defaults to
+	// VersionLatestCompatible.
+	Fallback VersionFallback
+
+	// Compare orders two versions, returning a negative number when a is
+	// older than b, a positive number when a is newer, and zero otherwise.
+	// It defaults to a natural ordering, which compares the runs of digits
+	// of the versions by value, as in v2 < v10 and 2024-05-01 < 2024-11-01.
+	Compare func(a, b string) int
+
+	// Unsupported responds to requests for which a route has no version to
+	// serve. It defaults to a 400 Bad Request response.
+	Unsupported http.Handler
+}
+
+// Versioning returns a middleware resolving the API version requested by a
+// request, for the routes registered with Mux#Version. Register it with Use,
+// so the version is known before routing the request, for example:
+//
+//	r.Use(chi.Versioning(chi.VersionOptions{
+//		Source: chi.VersionFromHeader("Api-Version"),
+//	}))
+//
+//	r.Version("2024-01-01", func(r chi.Router) {
+//		r.Get("/users/{id}", getUserV1)
+//		r.Get("/orders", listOrders)
+//	})
+//	r.Version("2024-05-01", func(r chi.Router) {
+//		r.Get("/users/{id}", getUserV2)
+//	})
+//
+// Here, a request for version 2024-06-01 is served by getUserV2 and
+// listOrders, and one for version 2024-02-01 by getUserV1 and listOrders.
+func Versioning(opts VersionOptions) func(http.Handler) http.Handler {
+	if opts.Source == nil {
+		panic("chi: Versioning requires a version source")
+	}
+	if opts.Compare == nil {
+		opts.Compare = compareVersions
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path := r.URL.Path
+			rctx := RouteContext(r.Context())
+			if rctx != nil {
+				path = routePath(rctx, r)
+			}
+
+			version, nextPath := opts.Source(r, path)
+			if rctx != nil && nextPath != path {
+				rctx.RoutePath = nextPath
+			}
+
+			state := &versionState{requested: version, opts: &opts}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), versionCtxKey, state)))
+		})
+	}
+}
+
+// RequestedVersion returns the API version requested by the request, as
+// resolved by the Versioning middleware, or "" if it didn't ask for one.
+func RequestedVersion(ctx context.Context) string {
+	if state, ok := ctx.Value(versionCtxKey).(*versionState); ok {
+		return state.requested
+	}
+	return ""
+}
+
+var versionCtxKey = &contextKey{"Version"}
+
+// versionState is the version requested by a request, with the options of
+// the Versioning middleware.
+type versionState struct {
+	requested string
+	opts      *VersionOptions
+}
+
+// versionHandler serves a route registered for several versions with the
+// handler of the version the request resolves to, or with the handler of
+// the route registered outside of Version when none does.
+type versionHandler struct {
+	versions []string
+	handlers []http.Handler
+	fallback http.Handler
+}
+
+// add registers the handler of the route for the version, replacing any
+// handler already registered for it.
+func (vh *versionHandler) add(version string, h http.Handler) *versionHandler {
+	for i, v := range vh.versions {
+		if v == version {
+			vh.handlers[i] = h
+			return vh
+		}
+	}
+	vh.versions = append(vh.versions, version)
+	vh.handlers = append(vh.handlers, h)
+	return vh
+}
+
+func (vh *versionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	state, _ := r.Context().Value(versionCtxKey).(*versionState)
+	if state == nil {
+		state = &versionState{opts: &VersionOptions{Compare: compareVersions}}
+	}
+
+	requested := state.requested
+	if requested == "" {
+		requested = state.opts.Default
+	}
+
+	if h := vh.resolve(requested, state.opts); h != nil {
+		h.ServeHTTP(w, r)
+		return
+	}
+	if vh.fallback != nil {
+		vh.fallback.ServeHTTP(w, r)
+		return
+	}
+	if state.opts.Unsupported != nil {
+		state.opts.Unsupported.ServeHTTP(w, r)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+}
+
+// resolve returns the handler of the version serving the requested version,
+// or nil if there is none.
+func (vh *versionHandler) resolve(requested string, opts *VersionOptions) http.Handler {
+	compare := opts.Compare
+	latest := func(ok func(v string) bool) http.Handler {
+		var h http.Handler
+		var hv string
+		for i, v := range vh.versions {
+			if ok(v) && (h == nil || compare(v, hv) > 0) {
+				h, hv = vh.handlers[i], v
+			}
+		}
+		return h
+	}
+
+	if requested == "" {
+		return latest(func(string) bool { return true })
+	}
+	for i, v := range vh.versions {
+		if compare(v, requested) == 0 {
+			return vh.handlers[i]
+		}
+	}
+
+	switch opts.Fallback {
+	case VersionLatest:
+		return latest(func(string) bool { return true })
+	case VersionLatestCompatible:
+		return latest(func(v string) bool { return compare(v, requested) < 0 })
+	}
+	return nil
+}
+
+// Metadata returns the metadata of the latest version of the route, along
+// with the versions of the route under the MetaVersions key.
+func (vh *versionHandler) Metadata() Metadata {
+	md := Metadata{}
+	if h := vh.resolve("", &VersionOptions{Compare: compareVersions}); h != nil {
+		for k, v := range HandlerMetadata(h) {
+			md[k] = v
+		}
+	}
+	md[MetaVersions] = append([]string(nil), vh.versions...)
+	return md
+}
+
+// compareVersions orders versions naturally, comparing their runs of digits
+// by value and the rest of their characters lexically.
+func compareVersions(a, b string) int {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			na, ra := cutDigits(a)
+			nb, rb := cutDigits(b)
+			na, nb = strings.TrimLeft(na, "0"), strings.TrimLeft(nb, "0")
+			if len(na) != len(nb) {
+				return len(na) - len(nb)
+			}
+			if c := strings.Compare(na, nb); c != 0 {
+				return c
+			}
+			a, b = ra, rb
+			continue
+		}
+		if a[0] != b[0] {
+			return int(a[0]) - int(b[0])
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) - len(b)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func cutDigits(s string) (digits, rest string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
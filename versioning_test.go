@@ -0,0 +1,262 @@
+package chi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func versionRouter(opts VersionOptions) *Mux {
+	text := func(s string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(s + " " + URLParam(r, "id")))
+		}
+	}
+
+	r := NewRouter()
+	r.Use(Versioning(opts))
+	r.Get("/ping", text("pong"))
+	r.Version("2024-01-01", func(r Router) {
+		r.Get("/users/{id}", text("users v1"))
+		r.Get("/orders", text("orders v1"))
+		r.Route("/admin", func(r Router) {
+			r.Get("/", text("admin v1"))
+		})
+	})
+	r.Version("2024-05-01", func(r Router) {
+		r.Get("/users/{id}", text("users v2"))
+		r.Route("/admin", func(r Router) {
+			r.Get("/", text("admin v2"))
+		})
+	})
+	return r
+}
+
+func serveVersion(h http.Handler, path string, header ...string) (int, string) {
+	r := httptest.NewRequest("GET", path, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Code, w.Body.String()
+}
+
+func TestVersionFromHeader(t *testing.T) {
+	r := versionRouter(VersionOptions{Source: VersionFromHeader("Api-Version")})
+
+	tests := []struct {
+		path, version string
+		status        int
+		body          string
+	}{
+		{"/users/1", "2024-01-01", 200, "users v1 1"},
+		{"/users/1", "2024-05-01", 200, "users v2 1"},
+		{"/users/1", "2024-03-01", 200, "users v1 1"},
+		{"/users/1", "2025-01-01", 200, "users v2 1"},
+		{"/users/1", "", 200, "users v2 1"},
+		{"/users/1", "2023-01-01", 400, "Bad Request\n"},
+		{"/orders", "2024-05-01", 200, "orders v1 "},
+		{"/ping", "2023-01-01", 200, "pong "},
+		{"/admin", "2024-01-01", 200, "admin v1 "},
+		{"/admin/", "2024-06-01", 200, "admin v2 "},
+	}
+	for _, tt := range tests {
+		status, body := serveVersion(r, tt.path, "Api-Version", tt.version)
+		if status != tt.status || body != tt.body {
+			t.Errorf("%s for version %q: expected %d %q, got %d %q", tt.path, tt.version, tt.status, tt.body, status, body)
+		}
+	}
+}
+
+func TestVersionFromMediaType(t *testing.T) {
+	r := versionRouter(VersionOptions{Source: VersionFromMediaType("acme")})
+
+	if _, body := serveVersion(r, "/users/1", "Accept", "text/html, application/vnd.acme.2024-01-01+json"); body != "users v1 1" {
+		t.Fatalf("unexpected body %q", body)
+	}
+	if _, body := serveVersion(r, "/users/1", "Accept", "application/vnd.acme+json; version=2024-01-01"); body != "users v1 1" {
+		t.Fatalf("unexpected body %q", body)
+	}
+	if _, body := serveVersion(r, "/users/1", "Accept", "application/json"); body != "users v2 1" {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
+
+func TestVersionFromPath(t *testing.T) {
+	text := func(s string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(s + " " + RequestedVersion(r.Context()) + " " + RouteContext(r.Context()).RoutePattern()))
+		}
+	}
+
+	r := NewRouter()
+	r.Use(Versioning(VersionOptions{Source: VersionFromPath(regexp.MustCompile(`^v[0-9]+$`))}))
+	r.Get("/ping", text("pong"))
+	r.Version("v1", func(r Router) {
+		r.Get("/users", text("users"))
+	})
+	r.Version("v10", func(r Router) {
+		r.Get("/users", text("users"))
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/v1/users", 200, "users v1 /users"},
+		{"/v10/users", 200, "users v10 /users"},
+		{"/v9/users", 200, "users v9 /users"},
+		{"/users", 200, "users  /users"},
+		{"/v2/ping", 200, "pong v2 /ping"},
+		{"/ping", 200, "pong  /ping"},
+		{"/v2/nope", 404, "404 page not found\n"},
+	}
+	for _, tt := range tests {
+		status, body := serveVersion(r, tt.path)
+		if status != tt.status || body != tt.body {
+			t.Errorf("%s: expected %d %q, got %d %q", tt.path, tt.status, tt.body, status, body)
+		}
+	}
+}
+
+func TestVersionFallback(t *testing.T) {
+	tests := []struct {
+		fallback VersionFallback
+		version  string
+		status   int
+		body     string
+	}{
+		{VersionExact, "2024-01-01", 200, "users v1 1"},
+		{VersionExact, "2024-03-01", 418, ""},
+		{VersionLatest, "2024-03-01", 200, "users v2 1"},
+		{VersionLatest, "2023-01-01", 200, "users v2 1"},
+		{VersionLatestCompatible, "2023-01-01", 418, ""},
+	}
+	for _, tt := range tests {
+		r := versionRouter(VersionOptions{
+			Source:   VersionFromHeader("Api-Version"),
+			Fallback: tt.fallback,
+			Unsupported: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			}),
+		})
+		status, body := serveVersion(r, "/users/1", "Api-Version", tt.version)
+		if status != tt.status || body != tt.body {
+			t.Errorf("fallback %d for version %q: expected %d %q, got %d %q", tt.fallback, tt.version, tt.status, tt.body, status, body)
+		}
+	}
+}
+
+func TestVersionDefault(t *testing.T) {
+	r := versionRouter(VersionOptions{Source: VersionFromHeader("Api-Version"), Default: "2024-01-01"})
+	if _, body := serveVersion(r, "/users/1"); body != "users v1 1" {
+		t.Fatalf("unexpected body %q", body)
+	}
+
+	// Without the Versioning middleware, the latest version is served.
+	r = NewRouter()
+	r.Version("1", func(r Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("v1")) })
+	})
+	r.Version("2", func(r Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("v2")) })
+	})
+	if _, body := serveVersion(r, "/"); body != "v2" {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
+
+func TestVersionPlainRoute(t *testing.T) {
+	text := func(s string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(s)) }
+	}
+	plain := func(r *Mux) { r.Get("/x", text("plain")) }
+	versioned := func(r *Mux) {
+		r.Version("2", func(r Router) { r.Get("/x", text("v2")) })
+		r.Version("3", func(r Router) { r.Get("/x", text("v3")) })
+	}
+
+	for _, order := range [][]func(r *Mux){{plain, versioned}, {versioned, plain}} {
+		r := NewRouter()
+		r.Use(Versioning(VersionOptions{Source: VersionFromHeader("Api-Version")}))
+		for _, fn := range order {
+			fn(r)
+		}
+
+		for version, want := range map[string]string{"1": "plain", "2": "v2", "3": "v3", "4": "v3", "": "v3"} {
+			if status, body := serveVersion(r, "/x", "Api-Version", version); status != 200 || body != want {
+				t.Errorf("version %q: expected 200 %q, got %d %q", version, want, status, body)
+			}
+		}
+	}
+}
+
+func TestVersionMountHandler(t *testing.T) {
+	r := NewRouter()
+	r.Use(Versioning(VersionOptions{Source: VersionFromHeader("V")}))
+	for _, version := range []string{"1", "2"} {
+		r.Version(version, func(r Router) {
+			sr := NewRouter()
+			sr.Get("/", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("m" + version)) })
+			r.Mount("/m", sr)
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/m/", nil)
+	req.Header.Set("V", "1")
+	h, pattern, _ := r.Handler(req)
+	if _, ok := h.(*versionHandler); !ok || pattern != "/m/" {
+		t.Fatalf("expected the version dispatcher of /m/, got %T %q", h, pattern)
+	}
+	if _, body := serveVersion(r, "/m/", "V", "1"); body != "m1" {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
+
+func TestVersionMetadata(t *testing.T) {
+	r := versionRouter(VersionOptions{Source: VersionFromHeader("Api-Version")})
+
+	var versions []string
+	Walk(r, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if route == "/users/{id}" {
+			versions = HandlerMetadata(handler)[MetaVersions].([]string)
+		}
+		return nil
+	})
+	if !slices.Equal(versions, []string{"2024-01-01", "2024-05-01"}) {
+		t.Fatalf("unexpected versions %q", versions)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v2", "v10", -1},
+		{"v10", "v2", 1},
+		{"v02", "v2", 0},
+		{"2024-05-01", "2024-11-01", -1},
+		{"1.2.3", "1.2", 1},
+		{"beta", "alpha", 1},
+	}
+	for _, tt := range tests {
+		got := compareVersions(tt.a, tt.b)
+		if (got < 0) != (tt.want < 0) || (got > 0) != (tt.want > 0) {
+			t.Errorf("compareVersions(%q, %q): expected %d, got %d", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
+
+func TestVersionPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+	}()
+	NewRouter().Version("", func(r Router) {})
+}